@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/services"
+)
+
+// runImport реализует команду `songs import [-format csv|jsonl] [-dry-run] [-enrich] <файл>`.
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "", "формат файла: csv или jsonl (по умолчанию по расширению)")
+	dryRun := fs.Bool("dry-run", false, "только проверить строки, ничего не сохраняя")
+	enrich := fs.Bool("enrich", false, "дополнять недостающие поля через внешний API")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		logger.Log.Fatal("Использование: songs import [-format csv|jsonl] [-dry-run] [-enrich] <файл>")
+	}
+	path := fs.Arg(0)
+
+	detected, err := services.DetectImportFormat(*format, path, "")
+	if err != nil {
+		logger.Log.Fatalf("Ошибка определения формата: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Log.Fatalf("Не удалось открыть файл: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := services.ParseImport(file, detected)
+	if err != nil {
+		logger.Log.Fatalf("Ошибка чтения файла импорта: %v", err)
+	}
+
+	cache.InitRedis()
+	database.Init()
+
+	job := services.RunImport(database.DB, rows, services.ImportOptions{
+		Format: detected,
+		DryRun: *dryRun,
+		Enrich: *enrich,
+	})
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(job); err != nil {
+		logger.Log.Fatalf("Ошибка вывода результата: %v", err)
+	}
+	if job.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"net"
+	"os"
+
 	"songs/config"
 	"songs/database"
 	"songs/internal/cache"
 	"songs/internal/handlers"
 	"songs/internal/logger"
+	"songs/internal/services"
 
 	_ "songs/docs"
 
@@ -15,23 +19,51 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(os.Args[2:])
+		return
+	}
+
 	logger.Log.Info("Старт приложениия")
 
 	cache.InitRedis()
 	database.Init()
+	if err := services.BackfillSongLinks(database.DB); err != nil {
+		logger.Log.Errorf("Ошибка переноса ссылок песен: %v", err)
+	}
+	if err := services.BackfillSongCredits(database.DB); err != nil {
+		logger.Log.Errorf("Ошибка заполнения участников песен: %v", err)
+	}
+	if err := services.BackfillSearchKeys(database.DB); err != nil {
+		logger.Log.Errorf("Ошибка заполнения ключей поиска: %v", err)
+	}
+	services.StartStatsFlusher(database.DB)
+	services.StartSuggestionsRefresher(database.DB)
+	services.StartWebhookDispatcher(database.DB)
+	services.StartEventRelay(database.DB)
 
 	router := gin.Default()
 	router.Use(gin.Logger())
 
-	router.GET("/songs", handlers.GetSongs)
-	router.GET("/songs/:id", handlers.GetSong)
-	router.GET("/songs/:id/text", handlers.GetSongText)
-	router.POST("/songs", handlers.AddSong)
-	router.PATCH("/songs/:id", handlers.PatchSong)
-	router.DELETE("/songs/:id", handlers.DeleteSong)
+	handlers.RegisterRoutes(router)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	grpcPort := config.Get("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		logger.Log.Fatalf("Ошибка запуска gRPC-сервера: %v", err)
+	}
+	go func() {
+		logger.Log.Debugf("gRPC-сервер запущен на порту %s", grpcPort)
+		if err := handlers.NewGRPCServer().Serve(listener); err != nil {
+			logger.Log.Fatalf("Ошибка gRPC-сервера: %v", err)
+		}
+	}()
+
 	port := config.Get("PORT")
 	if port == "" {
 		port = "8080"
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"songs/internal/models"
+	"songs/internal/services"
+	"songs/pkg/client"
+)
+
+// Как часто import опрашивает задачу импорта.
+const importPollInterval = 500 * time.Millisecond
+
+var errUsage = errors.New("неверные аргументы, см. songsctl <команда> -h")
+
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Использование: songsctl %s [флаги] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// songFilterFlags регистрирует флаги фильтров GET /songs.
+func songFilterFlags(fs *flag.FlagSet) *client.ListOptions {
+	opts := &client.ListOptions{}
+	fs.StringVar(&opts.Group, "group", "", "артист (с учётом псевдонимов)")
+	fs.StringVar(&opts.Role, "role", "", "роль артиста: primary, featured, composer, lyricist или producer")
+	fs.StringVar(&opts.Song, "song", "", "название песни")
+	fs.StringVar(&opts.ReleaseDate, "release-date", "", "дата релиза: YYYY, YYYY-MM или YYYY-MM-DD")
+	fs.StringVar(&opts.ReleasedFrom, "from", "", "релиз не раньше даты")
+	fs.StringVar(&opts.ReleasedTo, "to", "", "релиз не позже даты")
+	fs.StringVar(&opts.Text, "text", "", "фрагмент текста")
+	fs.StringVar(&opts.Link, "link", "", "ссылка на песню")
+	fs.StringVar(&opts.Album, "album", "", "название альбома")
+	fs.StringVar(&opts.AlbumType, "album-type", "", "тип альбома: album, ep или single")
+	fs.Func("platform", "платформы через запятую", listFlag(&opts.Platforms))
+	fs.Func("genre", "жанры через запятую", listFlag(&opts.Genres))
+	fs.Func("tag", "теги через запятую", listFlag(&opts.Tags))
+	fs.StringVar(&opts.GenreMode, "genre-mode", "", "режим фильтра по жанрам: or или and")
+	fs.StringVar(&opts.TagMode, "tag-mode", "", "режим фильтра по тегам: or или and")
+	return opts
+}
+
+func listFlag(target *[]string) func(string) error {
+	return func(value string) error {
+		*target = append(*target, strings.Split(value, ",")...)
+		return nil
+	}
+}
+
+// songID разбирает единственный аргумент команды — ID песни.
+func songID(fs *flag.FlagSet) (uint, error) {
+	if fs.NArg() != 1 {
+		return 0, errUsage
+	}
+	id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("некорректный ID песни: %q", fs.Arg(0))
+	}
+	return uint(id), nil
+}
+
+func runList(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("list", "")
+	opts := songFilterFlags(fs)
+	fs.StringVar(&opts.Sort, "sort", "", "сортировка: popularity, releaseDate или -releaseDate")
+	fs.IntVar(&opts.Page, "page", 1, "номер страницы")
+	fs.IntVar(&opts.PageSize, "page-size", 10, "песен на странице")
+	all := fs.Bool("all", false, "вывести все страницы")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return errUsage
+	}
+
+	if !*all {
+		songs, err := api.ListSongs(ctx, *opts)
+		if err != nil {
+			return err
+		}
+		return out.songs(songs)
+	}
+	songs := []models.Song{}
+	for song, err := range api.AllSongs(ctx, *opts) {
+		if err != nil {
+			return err
+		}
+		songs = append(songs, song)
+	}
+	return out.songs(songs)
+}
+
+func runSearch(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("search", "<запрос>")
+	limit := fs.Int("limit", 0, "сколько результатов вернуть")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	response, err := api.SearchSongs(ctx, strings.Join(fs.Args(), " "), *limit)
+	if err != nil {
+		return err
+	}
+	return out.search(response)
+}
+
+func runGet(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("get", "<id>")
+	fs.Parse(args)
+	id, err := songID(fs)
+	if err != nil {
+		return err
+	}
+	song, err := api.GetSong(ctx, id)
+	if err != nil {
+		return err
+	}
+	return out.song(song)
+}
+
+func runAdd(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("add", "<группа> <песня>")
+	noEnrich := fs.Bool("no-enrich", false, "не обращаться к внешнему API, взять данные из флагов")
+	var detail models.SongDetail
+	fs.StringVar(&detail.ReleaseDate, "date", "", "дата релиза (с -no-enrich)")
+	fs.StringVar(&detail.Text, "text", "", "текст песни (с -no-enrich)")
+	fs.StringVar(&detail.Link, "link", "", "ссылка на песню (с -no-enrich)")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return errUsage
+	}
+
+	var song models.Song
+	var err error
+	if *noEnrich {
+		if detail.ReleaseDate != "" {
+			if _, err := models.ParsePartialDate(detail.ReleaseDate); err != nil {
+				return err
+			}
+		}
+		song, err = api.CreateSongWithoutEnrichment(ctx, fs.Arg(0), fs.Arg(1), detail)
+	} else {
+		if detail.ReleaseDate != "" || detail.Text != "" || detail.Link != "" {
+			return errors.New("флаги -date, -text и -link используются только с -no-enrich")
+		}
+		song, err = api.CreateSong(ctx, fs.Arg(0), fs.Arg(1))
+	}
+	if err != nil {
+		return err
+	}
+	return out.song(song)
+}
+
+func runPatch(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("patch", "<id>")
+	group := fs.String("group", "", "новое имя артиста (меняется во всех его песнях)")
+	moveTo := fs.String("move-to", "", "перенести песню к другому артисту; \"A feat. B\" пересобирает участников")
+	title := fs.String("song", "", "название песни")
+	date := fs.String("date", "", "дата релиза: YYYY, YYYY-MM или YYYY-MM-DD; пустая строка сбрасывает дату")
+	text := fs.String("text", "", "текст песни; -text @файл читает текст из файла")
+	link := fs.String("link", "", "ссылка на песню")
+	lang := fs.String("lang", "", "язык текста")
+	fs.Parse(args)
+	id, err := songID(fs)
+	if err != nil {
+		return err
+	}
+
+	// Меняются только явно переданные флаги, поэтому можно, например, очистить
+	// ссылку через -link "".
+	var update models.SongUpdate
+	var visitErr error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "group":
+			update.GroupName = group
+		case "move-to":
+			update.MoveTo = moveTo
+		case "song":
+			update.Song = title
+		case "date":
+			parsed, err := models.ParsePartialDateUpdate(*date)
+			if err != nil {
+				visitErr = err
+				return
+			}
+			update.ReleaseDate = &parsed
+		case "text":
+			if path, ok := strings.CutPrefix(*text, "@"); ok {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					visitErr = err
+					return
+				}
+				*text = string(data)
+			}
+			update.Text = text
+		case "link":
+			update.Link = link
+		case "lang":
+			update.Lang = lang
+		}
+	})
+	if visitErr != nil {
+		return visitErr
+	}
+	if update == (models.SongUpdate{}) {
+		return errors.New("не передано ни одного поля для изменения")
+	}
+
+	song, err := api.PatchSong(ctx, id, update)
+	if err != nil {
+		return err
+	}
+	return out.song(song)
+}
+
+func runDelete(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("delete", "<id>")
+	fs.Parse(args)
+	id, err := songID(fs)
+	if err != nil {
+		return err
+	}
+	if err := api.DeleteSong(ctx, id); err != nil {
+		return err
+	}
+	return out.message(fmt.Sprintf("Песня %d удалена", id))
+}
+
+func runRestore(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("restore", "<id>")
+	usage := fs.Usage
+	fs.Usage = func() {
+		usage()
+		fmt.Fprint(fs.Output(), `
+Песня восстанавливается с тем же ID по событию song.deleted: поля, ссылки,
+участники, жанры и теги. Переводы, синхронизированный текст, места в альбомах
+и плейлистах, избранное и статистика возвращаются из архива, сохранённого при
+удалении. Теряются: места в удалённых с тех пор альбомах и плейлистах, удалённые
+жанры и теги, прослушивания, не сброшенные в БД к моменту удаления, а у песен,
+удалённых до появления архива, — переводы, синхронизированный текст, альбомы,
+плейлисты, избранное и статистика.
+`)
+	}
+	fs.Parse(args)
+	id, err := songID(fs)
+	if err != nil {
+		return err
+	}
+	song, err := api.RestoreSong(ctx, id)
+	if err != nil {
+		return err
+	}
+	return out.song(song)
+}
+
+func runEnrich(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("enrich", "<id>")
+	fs.Parse(args)
+	id, err := songID(fs)
+	if err != nil {
+		return err
+	}
+	song, err := api.EnrichSong(ctx, id)
+	if err != nil {
+		return err
+	}
+	return out.song(song)
+}
+
+func runImport(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("import", "<файл>")
+	format := fs.String("format", "", "формат файла: csv или jsonl (по умолчанию по расширению)")
+	dryRun := fs.Bool("dry-run", false, "только проверить строки, ничего не сохраняя")
+	enrich := fs.Bool("enrich", false, "дополнять недостающие поля через внешний API")
+	noWait := fs.Bool("no-wait", false, "не ждать завершения, вывести задачу сразу")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	path := fs.Arg(0)
+
+	detected, err := services.DetectImportFormat(*format, path, "")
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	job, err := api.ImportSongs(ctx, file, client.ImportOptions{Format: detected, DryRun: *dryRun, Enrich: *enrich})
+	if err != nil {
+		return err
+	}
+	if !*noWait {
+		if job, err = api.WaitImport(ctx, job.ID, importPollInterval); err != nil {
+			return err
+		}
+	}
+	if err := out.importJob(job); err != nil {
+		return err
+	}
+	if job.Failed > 0 {
+		return fmt.Errorf("строк с ошибками: %d", job.Failed)
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, api *client.Client, _ *printer, args []string) error {
+	fs := newFlagSet("export", "")
+	opts := songFilterFlags(fs)
+	format := fs.String("format", "json", "формат выгрузки: json, csv или ndjson")
+	path := fs.String("out", "", "файл для выгрузки (по умолчанию stdout)")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return errUsage
+	}
+
+	var w io.Writer = os.Stdout
+	if *path != "" {
+		file, err := os.Create(*path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return api.ExportSongs(ctx, w, *format, *opts)
+}
+
+func runVerses(ctx context.Context, api *client.Client, out *printer, args []string) error {
+	fs := newFlagSet("verses", "<id>")
+	var opts client.TextOptions
+	fs.StringVar(&opts.Type, "type", "", "только секции типа: verse, chorus, bridge и др.")
+	fs.StringVar(&opts.Lang, "lang", "", "язык перевода")
+	fs.IntVar(&opts.Page, "page", 0, "номер страницы (по умолчанию весь текст)")
+	fs.IntVar(&opts.PageSize, "page-size", 5, "секций на странице")
+	fs.Parse(args)
+	id, err := songID(fs)
+	if err != nil {
+		return err
+	}
+
+	if opts.Page > 0 {
+		text, err := api.GetSongText(ctx, id, opts)
+		if err != nil {
+			return err
+		}
+		return out.sections(text.Sections)
+	}
+	sections := []models.LyricsSection{}
+	for section, err := range api.AllVerses(ctx, id, opts) {
+		if err != nil {
+			return err
+		}
+		sections = append(sections, section)
+	}
+	return out.sections(sections)
+}
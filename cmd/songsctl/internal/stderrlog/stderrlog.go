@@ -0,0 +1,18 @@
+// Package stderrlog переводит журнал сервиса в stderr, чтобы он не смешивался
+// с выводом songsctl. Пакет подключается пустым импортом: его init выполняется
+// сразу после init пакета logger, раньше пакетов, которые уже пишут в журнал
+// при загрузке (config).
+package stderrlog
+
+import (
+	"os"
+
+	"songs/internal/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	logger.Log.SetOutput(os.Stderr)
+	logger.Log.SetLevel(logrus.WarnLevel)
+}
@@ -0,0 +1,97 @@
+// Команда songsctl — утилита оператора каталога: просмотр и поиск песен,
+// добавление, правка, удаление и восстановление, повторное обогащение,
+// импорт и экспорт файлов, вывод куплетов. Работает через API или, с флагом
+// -offline, напрямую с БД, обрабатывая те же запросы в своём процессе.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "songs/cmd/songsctl/internal/stderrlog"
+	"songs/pkg/client"
+)
+
+const usage = `Использование: songsctl [-api URL] [-offline] [-o table|json] <команда> [флаги] [аргументы]
+
+Команды:
+  list     [фильтры] [-all]               список песен
+  search   [-limit N] <запрос>            поиск с учётом опечаток
+  get      <id>                           песня целиком
+  add      [-no-enrich] <группа> <песня>  добавить песню
+  patch    [-group ...] <id>              изменить поля песни
+  delete   <id>                           удалить песню
+  restore  <id>                           восстановить удалённую песню
+  enrich   <id>                           заново запросить данные во внешнем API
+  import   [-format] [-dry-run] <файл>    импорт из csv или jsonl
+  export   [-format] [-out файл]          экспорт в json, csv или ndjson
+  verses   [-page N] <id>                 куплеты песни
+
+Флаги команды: songsctl <команда> -h
+`
+
+// command — подкоманда songsctl.
+type command func(ctx context.Context, api *client.Client, out *printer, args []string) error
+
+var commands = map[string]command{
+	"list":    runList,
+	"search":  runSearch,
+	"get":     runGet,
+	"add":     runAdd,
+	"patch":   runPatch,
+	"delete":  runDelete,
+	"restore": runRestore,
+	"enrich":  runEnrich,
+	"import":  runImport,
+	"export":  runExport,
+	"verses":  runVerses,
+}
+
+func main() {
+	defaultAPI := os.Getenv("SONGS_API_URL")
+	if defaultAPI == "" {
+		defaultAPI = "http://localhost:8080"
+	}
+	flags := flag.NewFlagSet("songsctl", flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	apiURL := flags.String("api", defaultAPI, "адрес API (по умолчанию SONGS_API_URL)")
+	offline := flags.Bool("offline", false, "работать напрямую с БД, без запущенного сервиса")
+	output := flags.String("o", outputTable, "формат вывода: table или json")
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	run, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Неизвестная команда: %s\n\n", flags.Arg(0))
+		flags.Usage()
+		os.Exit(2)
+	}
+	out, err := newPrinter(os.Stdout, *output)
+	if err != nil {
+		fail(err)
+	}
+
+	var api *client.Client
+	if *offline {
+		api, err = offlineClient()
+	} else {
+		api, err = client.New(*apiURL)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	if err := run(context.Background(), api, out, flags.Args()[1:]); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+	os.Exit(1)
+}
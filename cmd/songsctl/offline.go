@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/handlers"
+	"songs/pkg/client"
+
+	"github.com/gin-gonic/gin"
+)
+
+// offlineClient подключается к БД и Redis из настроек сервиса и возвращает
+// клиент, запросы которого обрабатывают маршруты API в этом же процессе.
+// Схема БД не меняется: миграции выполняет только сервис.
+func offlineClient() (*client.Client, error) {
+	cache.InitRedis()
+	database.Connect()
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	handlers.RegisterRoutes(router)
+	return client.New("http://songsctl.offline",
+		client.WithHTTPClient(&http.Client{Transport: routerTransport{router}}),
+		client.WithRetries(0, 0))
+}
+
+// routerTransport передаёт запрос клиента обработчику напрямую, без сети.
+// Ответ отдаётся, как только обработчик записал заголовки, а тело идёт через
+// канал io.Pipe: большая выгрузка не собирается в памяти целиком.
+type routerTransport struct {
+	handler http.Handler
+}
+
+func (t routerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, bodyWriter := io.Pipe()
+	w := &pipeResponseWriter{
+		header: http.Header{},
+		body:   bodyWriter,
+		resp: &http.Response{
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Body:          body,
+			ContentLength: -1,
+			Request:       req,
+		},
+		ready: make(chan struct{}),
+	}
+	go func() {
+		defer func() {
+			// Обработчик мог ничего не записать: тогда это пустой ответ 200.
+			w.WriteHeader(http.StatusOK)
+			bodyWriter.Close()
+			if req.Body != nil {
+				req.Body.Close()
+			}
+		}()
+		t.handler.ServeHTTP(w, req)
+	}()
+	<-w.ready
+	return w.resp, nil
+}
+
+// pipeResponseWriter — http.ResponseWriter, который пишет тело ответа в канал.
+type pipeResponseWriter struct {
+	header http.Header
+	body   *io.PipeWriter
+	resp   *http.Response
+	ready  chan struct{}
+	once   sync.Once
+}
+
+func (w *pipeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *pipeResponseWriter) WriteHeader(status int) {
+	w.once.Do(func() {
+		w.resp.StatusCode = status
+		w.resp.Status = fmt.Sprintf("%d %s", status, http.StatusText(status))
+		w.resp.Header = w.header.Clone()
+		close(w.ready)
+	})
+}
+
+func (w *pipeResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+// Flush нужен gin для потоковых ответов; данные и так уходят читателю сразу.
+func (w *pipeResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"songs/internal/models"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer выводит результаты команд таблицей для человека или JSON для скриптов.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable:
+		return &printer{w: w}, nil
+	case outputJSON:
+		return &printer{w: w, json: true}, nil
+	}
+	return nil, fmt.Errorf("неизвестный формат вывода %q: ожидается table или json", format)
+}
+
+func (p *printer) encode(value interface{}) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// table выводит строки колонками; первая строка — заголовок.
+func (p *printer) table(rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) songs(songs []models.Song) error {
+	if p.json {
+		return p.encode(songs)
+	}
+	rows := [][]string{{"ID", "АРТИСТ", "ПЕСНЯ", "РЕЛИЗ", "ССЫЛКА"}}
+	for _, song := range songs {
+		rows = append(rows, []string{fmt.Sprint(song.ID), song.Artist.Name, song.Song, releaseDate(song), song.Link})
+	}
+	return p.table(rows)
+}
+
+func (p *printer) song(song models.Song) error {
+	if p.json {
+		return p.encode(song)
+	}
+	rows := [][]string{
+		{"ID:", fmt.Sprint(song.ID)},
+		{"Артист:", song.Artist.Name},
+		{"Песня:", song.Song},
+		{"Релиз:", releaseDate(song)},
+		{"Язык:", song.Lang},
+		{"Ссылка:", song.Link},
+	}
+	for _, link := range song.Links {
+		rows = append(rows, []string{"", link.Platform + ": " + link.URL})
+	}
+	for _, credit := range song.Credits {
+		rows = append(rows, []string{"Участник:", credit.Artist.Name + " (" + credit.Role + ")"})
+	}
+	if names := genreNames(song.Genres); names != "" {
+		rows = append(rows, []string{"Жанры:", names})
+	}
+	if names := tagNames(song.Tags); names != "" {
+		rows = append(rows, []string{"Теги:", names})
+	}
+	rows = append(rows, []string{"Обновлена:", song.UpdatedAt.Format("2006-01-02 15:04:05")})
+	if err := p.table(rows); err != nil {
+		return err
+	}
+	if song.Text != "" {
+		_, err := fmt.Fprintf(p.w, "\n%s\n", song.Text)
+		return err
+	}
+	return nil
+}
+
+func (p *printer) search(response models.SongSearchResponse) error {
+	if p.json {
+		return p.encode(response)
+	}
+	if response.DidYouMean != "" {
+		fmt.Fprintf(p.w, "Возможно, вы имели в виду: %s\n\n", response.DidYouMean)
+	}
+	rows := [][]string{{"ОЦЕНКА", "ID", "АРТИСТ", "ПЕСНЯ"}}
+	for _, result := range response.Results {
+		rows = append(rows, []string{
+			fmt.Sprintf("%.2f", result.Score), fmt.Sprint(result.Song.ID), result.Song.Artist.Name, result.Song.Song,
+		})
+	}
+	return p.table(rows)
+}
+
+func (p *printer) importJob(job models.ImportJob) error {
+	if p.json {
+		return p.encode(job)
+	}
+	rows := [][]string{
+		{"Задача:", job.ID},
+		{"Статус:", job.Status},
+		{"Строк:", fmt.Sprint(job.Total)},
+		{"Создано:", fmt.Sprint(job.Created)},
+		{"Обновлено:", fmt.Sprint(job.Updated)},
+		{"С ошибками:", fmt.Sprint(job.Failed)},
+	}
+	if err := p.table(rows); err != nil {
+		return err
+	}
+	for _, row := range job.Errors {
+		if row.Error != "" {
+			fmt.Fprintf(p.w, "строка %d: %s\n", row.Line, row.Error)
+		} else {
+			fmt.Fprintf(p.w, "строка %d: предупреждение: %s\n", row.Line, row.Warning)
+		}
+	}
+	return nil
+}
+
+func (p *printer) sections(sections []models.LyricsSection) error {
+	if p.json {
+		return p.encode(sections)
+	}
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(p.w)
+		}
+		label := section.Label
+		if label == "" {
+			label = fmt.Sprintf("%s %d", section.Type, section.Number)
+		}
+		fmt.Fprintf(p.w, "[%s]\n%s\n", label, strings.Join(section.Lines, "\n"))
+	}
+	return nil
+}
+
+// message выводит сообщение об успешной операции без данных.
+func (p *printer) message(text string) error {
+	if p.json {
+		return p.encode(models.MessageResponse{Message: text})
+	}
+	_, err := fmt.Fprintln(p.w, text)
+	return err
+}
+
+func releaseDate(song models.Song) string {
+	if song.ReleaseDate == nil {
+		return ""
+	}
+	return song.ReleaseDate.String()
+}
+
+func genreNames(genres []models.Genre) string {
+	names := make([]string, len(genres))
+	for i, genre := range genres {
+		names[i] = genre.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func tagNames(tags []models.Tag) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return strings.Join(names, ", ")
+}
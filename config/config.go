@@ -16,6 +16,7 @@ func init() {
 	}
 	AppConfig = map[string]string{
 		"PORT":          os.Getenv("PORT"),
+		"GRPC_PORT":     os.Getenv("GRPC_PORT"),
 		"DATABASE_URL":  os.Getenv("DATABASE_URL"),
 		"MUSIC_API_URL": os.Getenv("MUSIC_API_URL"),
 	}
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"strings"
+
 	"songs/config"
 	"songs/internal/logger"
 	"songs/internal/models"
@@ -11,21 +13,102 @@ import (
 
 var DB *gorm.DB
 
+// Init подключается к БД и приводит схему к текущим моделям. Вызывается сервисом при старте.
 func Init() {
 	logger.Log.Info("Инициализация БД")
+	db := open()
+
+	// pg_trgm нужен для нечёткого поиска по названиям. Если расширение нельзя
+	// создать (нет прав), поиск работает, но без индексов и с ошибками на операторах %.
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		logger.Log.Errorf("Не удалось подключить расширение pg_trgm: %v", err)
+	}
+
+	if err := migrateReleaseDates(db); err != nil {
+		logger.Log.Fatalf("Ошибка перевода дат релиза в новый формат: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Song{}, &models.SyncedLyrics{}, &models.SongTranslation{}, &models.SongLink{}, &models.SongCredit{}, &models.ArtistAlias{},
+		&models.Album{}, &models.AlbumTrack{}, &models.Genre{}, &models.Tag{},
+		&models.Playlist{}, &models.PlaylistItem{}, &models.SongStats{}, &models.Favorite{}, &models.SongArchive{},
+		&models.Event{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}); err != nil {
+		logger.Log.Fatalf("Ошибка миграции: %v", err)
+	}
+	for _, index := range []string{
+		"CREATE INDEX IF NOT EXISTS idx_songs_search_key_trgm ON songs USING gin (search_key gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_artists_search_key_trgm ON artists USING gin (search_key gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_artist_aliases_search_key_trgm ON artist_aliases USING gin (search_key gin_trgm_ops)",
+	} {
+		if err := db.Exec(index).Error; err != nil {
+			logger.Log.Errorf("Ошибка создания триграммного индекса: %v", err)
+		}
+	}
+	createArtistNameIndex(db)
+	DB = db
+	logger.Log.Info("Успешное подключение к БД")
+}
+
+// createArtistNameIndex создаёт уникальный индекс по LOWER(name) артистов: артисты
+// ищутся без учёта регистра, и параллельное создание «Muse» и «MUSE» должно
+// упираться в один индекс. Если в БД уже есть имена, различающиеся только
+// регистром, индекс не создаётся, а дубликаты выводятся в лог для объединения.
+func createArtistNameIndex(db *gorm.DB) {
+	err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_name_lower ON artists (LOWER(name))").Error
+	if err == nil {
+		return
+	}
+	var duplicates []string
+	if dupErr := db.Raw(`SELECT string_agg(name || ' (id ' || id || ')', ', ' ORDER BY id) FROM artists
+		GROUP BY LOWER(name) HAVING COUNT(*) > 1`).Scan(&duplicates).Error; dupErr != nil {
+		logger.Log.Errorf("Ошибка создания индекса имён артистов без учёта регистра: %v", err)
+		return
+	}
+	logger.Log.Errorf("Ошибка создания индекса имён артистов без учёта регистра: %v. "+
+		"Артисты, различающиеся только регистром: %s; объедините их через POST /artists/{id}/merge и перезапустите сервис, "+
+		"до этого новые артисты не создаются", err, strings.Join(duplicates, "; "))
+}
+
+// Connect только подключается к БД, не меняя схему: для утилит, которые
+// работают с уже развёрнутой БД сервиса.
+func Connect() {
+	DB = open()
+	logger.Log.Info("Успешное подключение к БД")
+}
+
+func open() *gorm.DB {
 	dsn := config.Get("DATABASE_URL")
 	if dsn == "" {
 		logger.Log.Fatal("DATABASE_URL не задан")
 	}
-
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		logger.Log.Fatalf("Ошибка подключения к БД: %v", err)
 	}
+	return db
+}
 
-	if err := db.AutoMigrate(&models.Song{}); err != nil {
-		logger.Log.Fatalf("Ошибка миграции: %v", err)
+// migrateReleaseDates переводит release_date песен и альбомов из timestamptz в строки
+// с точностью («2006-07-16»). Дата берётся в часовом поясе сессии, в котором её
+// и записывали, а нулевые даты (0001-01-01 вместо неизвестной) становятся NULL.
+// Таблицы, где колонка уже строковая или её ещё нет, пропускаются.
+func migrateReleaseDates(db *gorm.DB) error {
+	for _, table := range []string{"songs", "albums"} {
+		var dataType string
+		err := db.Raw(`SELECT data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = 'release_date'`, table).
+			Scan(&dataType).Error
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(dataType, "timestamp") && dataType != "date" {
+			continue
+		}
+		err = db.Exec(`ALTER TABLE ` + table + ` ALTER COLUMN release_date TYPE varchar(10) USING
+			CASE WHEN release_date IS NULL OR release_date < '0002-01-01' THEN NULL
+				ELSE to_char(release_date, 'YYYY-MM-DD') END`).Error
+		if err != nil {
+			return err
+		}
+		logger.Log.Infof("Даты релиза в таблице %s переведены в формат с точностью", table)
 	}
-	DB = db
-	logger.Log.Info("Успешное подключение к БД")
+	return nil
 }
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"songs/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTxAttempts = 3
+	txRetryDelay  = 20 * time.Millisecond
+)
+
+// WithTransaction выполняет fn в транзакции. Если транзакция упала из-за
+// конфликта сериализации или взаимной блокировки, она повторяется целиком.
+func WithTransaction(fn func(tx *gorm.DB) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = DB.Transaction(fn)
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+		logger.Log.Warnf("Конфликт транзакции, попытка %d из %d: %v", attempt, maxTxAttempts, err)
+		time.Sleep(time.Duration(attempt) * txRetryDelay)
+	}
+	return err
+}
+
+// isRetryable сообщает, можно ли безопасно повторить транзакцию:
+// 40001 — serialization_failure, 40P01 — deadlock_detected.
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
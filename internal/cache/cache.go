@@ -2,32 +2,308 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
+	"sync"
+	"time"
+
 	"songs/internal/logger"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// Размер и TTL локального кеша, который используется вместо Redis.
+	localCacheSize = 1000
+	localCacheTTL  = time.Minute
+	// Как часто проверяем, не поднялся ли Redis.
+	redisRetryInterval = 10 * time.Second
+	// Сколько удалённых во время недоступности Redis ключей запоминаем,
+	// чтобы удалить их из Redis после восстановления. Сверх лимита запоминается
+	// только пространство ключей (song:), и после восстановления оно удаляется целиком.
+	maxPendingInvalidations = 10000
+	// Сколько ключей удаляется одной командой при очистке пространства ключей.
+	scanBatchSize = 1000
+)
+
 var (
 	Rdb *redis.Client
 	ctx = context.Background()
+
+	local = newLRU(localCacheSize)
+
+	mu                   sync.RWMutex
+	redisConfigured      bool
+	redisUp              bool
+	degradedSince        time.Time
+	lastError            string
+	pendingInvalidations = make(map[string]struct{})
+	pendingNamespaces    = make(map[string]struct{})
 )
 
+// Status описывает текущее состояние кеша.
+type Status struct {
+	Backend       string     `json:"backend" example:"redis"`
+	Degraded      bool       `json:"degraded"`
+	DegradedSince *time.Time `json:"degradedSince,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LocalEntries  int        `json:"localEntries"`
+}
+
 func InitRedis() {
 	redisAddr := os.Getenv("REDIS_ADDR")
 	if redisAddr == "" {
-		logger.Log.Error("REDIS_ADDR не задан")
+		logger.Log.Warn("REDIS_ADDR не задан, используется локальный кеш в памяти")
+		return
 	}
+	redisConfigured = true
 
 	Rdb = redis.NewClient(&redis.Options{
-		Addr:     redisAddr,
-		Password: "",
-		DB:       0,
+		Addr:         redisAddr,
+		Password:     "",
+		DB:           0,
+		DialTimeout:  time.Second,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+		MaxRetries:   1,
 	})
 
 	if err := Rdb.Ping(ctx).Err(); err != nil {
 		logger.Log.Errorf("Не удалось подключиться к Redis: %v", err)
+		markDown(err)
+	} else {
+		markUp()
+		logger.Log.Info("Успешно подключились к Redis")
+	}
+
+	go watchRedis()
+}
+
+// Available сообщает, можно ли сейчас обращаться к Redis напрямую через Rdb.
+func Available() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return redisUp
+}
+
+// Get возвращает значение по ключу. Пока Redis недоступен, значение берётся из локального кеша.
+func Get(key string) ([]byte, bool) {
+	if Available() {
+		data, err := Rdb.Get(ctx, key).Bytes()
+		if err == nil {
+			return data, true
+		}
+		if errors.Is(err, redis.Nil) {
+			return nil, false
+		}
+		markDown(err)
+	}
+	return local.get(key)
+}
+
+// Set сохраняет значение в Redis, а при его недоступности — в локальный кеш.
+func Set(key string, value []byte, ttl time.Duration) {
+	if Available() {
+		err := Rdb.Set(ctx, key, value, ttl).Err()
+		if err == nil {
+			return
+		}
+		markDown(err)
+	}
+	if ttl <= 0 || ttl > localCacheTTL {
+		ttl = localCacheTTL
+	}
+	local.set(key, value, ttl)
+}
+
+// Del удаляет ключи из кеша. Если Redis недоступен, ключи запоминаются
+// и будут удалены из Redis после восстановления соединения.
+func Del(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	for _, key := range keys {
+		local.del(key)
+	}
+	if Rdb == nil {
+		return
+	}
+	for {
+		if Available() {
+			err := Rdb.Del(ctx, keys...).Err()
+			if err == nil {
+				return
+			}
+			markDown(err)
+		}
+
+		mu.Lock()
+		// Redis мог вернуться в работу, пока мы ждали блокировку: тогда
+		// отложенные ключи уже никто не удалит, удаляем сами.
+		if redisUp {
+			mu.Unlock()
+			continue
+		}
+		for _, key := range keys {
+			if len(pendingInvalidations) < maxPendingInvalidations {
+				pendingInvalidations[key] = struct{}{}
+				continue
+			}
+			namespace := keyNamespace(key)
+			if _, ok := pendingNamespaces[namespace]; !ok {
+				logger.Log.Warnf("Превышен лимит отложенных инвалидаций кеша, после восстановления Redis будут удалены все ключи %s*", namespace)
+				pendingNamespaces[namespace] = struct{}{}
+			}
+		}
+		mu.Unlock()
+		return
+	}
+}
+
+// keyNamespace возвращает префикс ключа до первого двоеточия включительно
+// ("song:" для "song:42") или сам ключ, если двоеточия нет.
+func keyNamespace(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i+1]
+	}
+	return key
+}
+
+// GetStatus возвращает состояние кеша для health-check.
+func GetStatus() Status {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	status := Status{
+		Backend:      "redis",
+		Degraded:     redisConfigured && !redisUp,
+		LastError:    lastError,
+		LocalEntries: local.len(),
+	}
+	if !redisUp {
+		status.Backend = "memory"
+	}
+	if status.Degraded {
+		since := degradedSince
+		status.DegradedSince = &since
+	}
+	return status
+}
+
+func markDown(err error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	lastError = err.Error()
+	if !redisUp && !degradedSince.IsZero() {
 		return
 	}
-	logger.Log.Info("Успешно подключились к Redis")
+	redisUp = false
+	degradedSince = time.Now()
+	logger.Log.Warnf("Redis недоступен, переключаемся на локальный кеш: %v", err)
+}
+
+func markUp() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	redisUp = true
+	degradedSince = time.Time{}
+	lastError = ""
+}
+
+// watchRedis периодически проверяет Redis, пока он недоступен, и возвращает
+// его в работу после восстановления.
+func watchRedis() {
+	ticker := time.NewTicker(redisRetryInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if Available() {
+			continue
+		}
+		if err := Rdb.Ping(ctx).Err(); err != nil {
+			logger.Log.Debugf("Redis по-прежнему недоступен: %v", err)
+			mu.Lock()
+			lastError = err.Error()
+			mu.Unlock()
+			continue
+		}
+		if err := flushPendingInvalidations(); err != nil {
+			logger.Log.Errorf("Не удалось применить отложенные инвалидации кеша: %v", err)
+			continue
+		}
+		logger.Log.Info("Соединение с Redis восстановлено")
+	}
+}
+
+// flushPendingInvalidations удаляет из Redis отложенные ключи и пространства
+// ключей и возвращает Redis в работу. Пока идёт удаление, Del может отложить
+// новые ключи, поэтому проверка, что отложенных не осталось, и markUp выполняются
+// под одной блокировкой, а удаление повторяется, пока они есть.
+func flushPendingInvalidations() error {
+	for {
+		mu.Lock()
+		if len(pendingInvalidations) == 0 && len(pendingNamespaces) == 0 {
+			local.purge()
+			redisUp = true
+			degradedSince = time.Time{}
+			lastError = ""
+			mu.Unlock()
+			return nil
+		}
+		keys := make([]string, 0, len(pendingInvalidations))
+		for key := range pendingInvalidations {
+			keys = append(keys, key)
+		}
+		namespaces := make([]string, 0, len(pendingNamespaces))
+		for namespace := range pendingNamespaces {
+			namespaces = append(namespaces, namespace)
+		}
+		mu.Unlock()
+
+		for _, namespace := range namespaces {
+			if err := deleteNamespace(namespace); err != nil {
+				return err
+			}
+		}
+		if len(keys) > 0 {
+			if err := Rdb.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+
+		mu.Lock()
+		for _, key := range keys {
+			delete(pendingInvalidations, key)
+		}
+		for _, namespace := range namespaces {
+			delete(pendingNamespaces, namespace)
+		}
+		mu.Unlock()
+		logger.Log.Infof("Удалено из Redis ключей после восстановления: %d, пространств ключей: %d", len(keys), len(namespaces))
+	}
+}
+
+// deleteNamespace удаляет из Redis все ключи с префиксом namespace.
+func deleteNamespace(namespace string) error {
+	iter := Rdb.Scan(ctx, 0, namespace+"*", scanBatchSize).Iterator()
+	batch := make([]string, 0, scanBatchSize)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == scanBatchSize {
+			if err := Rdb.Del(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return Rdb.Del(ctx, batch...).Err()
+	}
+	return nil
 }
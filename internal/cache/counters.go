@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Атомарно читает хеш счётчиков и удаляет его, чтобы инкременты, пришедшие
+// после чтения, попали уже в следующий сброс.
+var drainScript = redis.NewScript(`
+local values = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return values`)
+
+var (
+	countersMu    sync.Mutex
+	localCounters = make(map[string]map[string]float64)
+)
+
+// IncrCounter увеличивает буферизованный счётчик field в хеше key. Пока Redis
+// недоступен, счётчик копится в памяти процесса.
+func IncrCounter(key, field string, delta float64) {
+	if Available() {
+		err := Rdb.HIncrByFloat(ctx, key, field, delta).Err()
+		if err == nil {
+			return
+		}
+		markDown(err)
+	}
+
+	countersMu.Lock()
+	defer countersMu.Unlock()
+	if localCounters[key] == nil {
+		localCounters[key] = make(map[string]float64)
+	}
+	localCounters[key][field] += delta
+}
+
+// DrainCounters забирает накопленные счётчики хеша key и обнуляет буфер.
+// Счётчики из Redis и из памяти процесса складываются.
+func DrainCounters(key string) map[string]float64 {
+	countersMu.Lock()
+	result := localCounters[key]
+	delete(localCounters, key)
+	countersMu.Unlock()
+	if result == nil {
+		result = make(map[string]float64)
+	}
+
+	if !Available() {
+		return result
+	}
+	values, err := drainScript.Run(ctx, Rdb, []string{key}).StringSlice()
+	if err != nil {
+		markDown(err)
+		return result
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		delta, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			continue
+		}
+		result[values[i]] += delta
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"songs/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Публикует сообщение, только если его номер больше последнего опубликованного
+// в канал. Так несколько экземпляров сервиса, пересылающих одну и ту же
+// последовательность, отправляют каждое сообщение ровно один раз и по порядку.
+var publishOnceScript = redis.NewScript(`
+local last = tonumber(redis.call('GET', KEYS[1]) or '0')
+local seq = tonumber(ARGV[1])
+if seq <= last then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[3])
+redis.call('PUBLISH', KEYS[2], ARGV[2])
+return 1`)
+
+// Сколько хранится номер последнего опубликованного сообщения. Истекает, чтобы
+// после пересоздания БД нумерация с нуля снова публиковалась.
+const publishCursorTTL = 24 * time.Hour
+
+// PublishOnce публикует payload в канал channel, если seq больше последнего
+// опубликованного номера, хранящегося в cursorKey. Возвращает false, если
+// сообщение уже опубликовано другим экземпляром. Ошибка означает, что Redis
+// недоступен и сообщение нужно доставить иначе.
+func PublishOnce(channel, cursorKey string, seq uint64, payload []byte) (bool, error) {
+	if !Available() {
+		return false, redis.ErrClosed
+	}
+	published, err := publishOnceScript.Run(ctx, Rdb, []string{cursorKey, channel},
+		seq, payload, int(publishCursorTTL.Seconds())).Int()
+	if err != nil {
+		markDown(err)
+		return false, err
+	}
+	return published == 1, nil
+}
+
+// Subscribe передаёт handler сообщения канала channel, пока не отменён ctx.
+// Если Redis не настроен, возвращает false и ничего не делает. Переподключение
+// после сбоев go-redis выполняет сам.
+func Subscribe(ctx context.Context, channel string, handler func(payload []byte)) bool {
+	if Rdb == nil {
+		return false
+	}
+	pubsub := Rdb.Subscribe(ctx, channel)
+	go func() {
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					logger.Log.Warnf("Подписка на канал %s Redis закрыта", channel)
+					return
+				}
+				handler([]byte(message.Payload))
+			}
+		}
+	}()
+	return true
+}
@@ -0,0 +1,237 @@
+// internal/handlers/album_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/search"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetAlbums godoc
+// @Summary Получение списка альбомов
+// @Description Возвращает альбомы с артистами с фильтрацией и пагинацией. Треки не включаются, их возвращает GET /albums/{id}.
+// @Tags albums
+// @Produce json
+// @Param group query string false "Название группы для фильтрации (регистр не важен, учитываются псевдонимы)"
+// @Param title query string false "Название альбома для фильтрации (регистр не важен)"
+// @Param type query string false "Тип: album, ep или single"
+// @Param page query int false "Номер страницы" default(1)
+// @Param pageSize query int false "Размер страницы" default(10)
+// @Success 200 {array} models.Album
+// @Failure 500 {object} models.ErrorResponse
+// @Router /albums [get]
+func GetAlbums(c *gin.Context) {
+	logger.Log.Info("Получение списка альбомов")
+	query := database.DB.Preload("Artist").Model(&models.Album{})
+
+	if group := c.Query("group"); group != "" {
+		query = query.Where("albums.artist_id IN (?)", artistsMatching(group))
+	}
+	if title := c.Query("title"); title != "" {
+		query = query.Where("albums.title ILIKE ?", search.Contains(title))
+	}
+	if albumType := c.Query("type"); albumType != "" {
+		query = query.Where("albums.type = ?", strings.ToLower(albumType))
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	albums := []models.Album{}
+	if err := query.Order("albums.id").Limit(pageSize).Offset((page - 1) * pageSize).Find(&albums).Error; err != nil {
+		logger.Log.Errorf("Ошибка при получении альбомов: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, albums)
+}
+
+// GetAlbum godoc
+// @Summary Получение альбома
+// @Description Возвращает альбом с артистом и треклистом по порядку, включая данные песен.
+// @Tags albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} models.Album
+// @Failure 404 {object} models.ErrorResponse "Альбом не найден"
+// @Router /albums/{id} [get]
+func GetAlbum(c *gin.Context) {
+	logger.Log.Infof("Получение альбома id: %s", c.Param("id"))
+	albumID, ok := parseAlbumID(c)
+	if !ok {
+		return
+	}
+
+	album, err := services.FindAlbum(database.DB, albumID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения альбома: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, album)
+}
+
+// AddAlbum godoc
+// @Summary Добавление альбома
+// @Description Создаёт альбом. Артист находится по имени или создаётся. Если передан songIds, треки добавляются в указанном порядке.
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param album body models.AlbumInput true "Данные альбома"
+// @Success 201 {object} models.Album
+// @Failure 400 {object} models.ErrorResponse "Некорректные данные"
+// @Router /albums [post]
+func AddAlbum(c *gin.Context) {
+	logger.Log.Info("Добавление альбома")
+	var input models.AlbumInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON альбома: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var album models.Album
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		album, err = services.CreateAlbum(tx, input)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка создания альбома: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Infof("Альбом создан, id: %d", album.ID)
+	c.JSON(http.StatusCreated, album)
+}
+
+// PatchAlbum godoc
+// @Summary Частичное обновление альбома
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param album body models.AlbumUpdate true "Поля для обновления"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} models.ErrorResponse "Некорректные данные"
+// @Failure 404 {object} models.ErrorResponse "Альбом не найден"
+// @Router /albums/{id} [patch]
+func PatchAlbum(c *gin.Context) {
+	logger.Log.Infof("Обновление альбома id: %s", c.Param("id"))
+	albumID, ok := parseAlbumID(c)
+	if !ok {
+		return
+	}
+
+	var input models.AlbumUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON альбома: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var album models.Album
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		album, err = services.UpdateAlbum(tx, albumID, input)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка обновления альбома: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, album)
+}
+
+// PutAlbumTracks godoc
+// @Summary Замена треклиста альбома
+// @Description Полностью заменяет треклист: позиции нумеруются с 1 в порядке songIds. Пустой список очищает треклист.
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param tracks body models.AlbumTracksInput true "ID песен по порядку"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} models.ErrorResponse "Несуществующие или повторяющиеся песни"
+// @Failure 404 {object} models.ErrorResponse "Альбом не найден"
+// @Router /albums/{id}/tracks [put]
+func PutAlbumTracks(c *gin.Context) {
+	logger.Log.Infof("Замена треклиста альбома id: %s", c.Param("id"))
+	albumID, ok := parseAlbumID(c)
+	if !ok {
+		return
+	}
+
+	var input models.AlbumTracksInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON треклиста: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var album models.Album
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		if _, err := services.SetAlbumTracks(tx, albumID, input.SongIDs); err != nil {
+			return err
+		}
+		var err error
+		album, err = services.FindAlbum(tx, albumID)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка замены треклиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, album)
+}
+
+// DeleteAlbum godoc
+// @Summary Удаление альбома
+// @Description Удаляет альбом и его треклист. Песни остаются в каталоге.
+// @Tags albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} models.MessageResponse "Альбом удалён"
+// @Failure 404 {object} models.ErrorResponse "Альбом не найден"
+// @Router /albums/{id} [delete]
+func DeleteAlbum(c *gin.Context) {
+	logger.Log.Infof("Удаление альбома id: %s", c.Param("id"))
+	albumID, ok := parseAlbumID(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteAlbum(database.DB, albumID); err != nil {
+		logger.Log.Errorf("Ошибка удаления альбома: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Альбом удалён"})
+}
+
+// parseAlbumID разбирает ID альбома из пути. При некорректном значении отвечает 404.
+func parseAlbumID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrAlbumNotFound.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,167 @@
+// internal/handlers/artist_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetArtist godoc
+// @Summary Получение артиста
+// @Description Возвращает артиста с псевдонимами.
+// @Tags artists
+// @Produce json
+// @Param id path int true "ID артиста"
+// @Success 200 {object} models.Artist
+// @Failure 404 {object} models.ErrorResponse "Артист не найден"
+// @Router /artists/{id} [get]
+func GetArtist(c *gin.Context) {
+	logger.Log.Infof("Получение артиста id: %s", c.Param("id"))
+	artistID, ok := parseArtistID(c)
+	if !ok {
+		return
+	}
+
+	artist, err := services.FindArtist(database.DB, artistID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения артиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, artist)
+}
+
+// AddArtistAlias godoc
+// @Summary Добавление псевдонима артиста
+// @Description Добавляет другое написание имени артиста. По псевдониму артист находится при добавлении песен (новый артист не создаётся) и в фильтре group. Повторное добавление ничего не меняет.
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID артиста"
+// @Param alias body models.ArtistAliasInput true "Псевдоним"
+// @Success 201 {object} models.ArtistAlias
+// @Failure 400 {object} models.ErrorResponse "Пустой псевдоним или совпадает с именем артиста"
+// @Failure 404 {object} models.ErrorResponse "Артист не найден"
+// @Failure 409 {object} models.ErrorResponse "Имя занято другим артистом"
+// @Router /artists/{id}/aliases [post]
+func AddArtistAlias(c *gin.Context) {
+	artistID, ok := parseArtistID(c)
+	if !ok {
+		return
+	}
+	var input models.ArtistAliasInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON псевдонима: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Infof("Добавление псевдонима %q артисту id: %d", input.Name, artistID)
+
+	var alias models.ArtistAlias
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		alias, err = services.AddArtistAlias(tx, artistID, input.Name)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка добавления псевдонима: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, alias)
+}
+
+// DeleteArtistAlias godoc
+// @Summary Удаление псевдонима артиста
+// @Tags artists
+// @Produce json
+// @Param id path int true "ID артиста"
+// @Param aliasId path int true "ID псевдонима"
+// @Success 200 {object} models.MessageResponse "Псевдоним удалён"
+// @Failure 404 {object} models.ErrorResponse "Псевдоним не найден"
+// @Router /artists/{id}/aliases/{aliasId} [delete]
+func DeleteArtistAlias(c *gin.Context) {
+	logger.Log.Infof("Удаление псевдонима %s артиста id: %s", c.Param("aliasId"), c.Param("id"))
+	artistID, ok := parseArtistID(c)
+	if !ok {
+		return
+	}
+	aliasID, err := strconv.ParseUint(c.Param("aliasId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrArtistAliasNotFound.Error()})
+		return
+	}
+
+	err = database.WithTransaction(func(tx *gorm.DB) error {
+		return services.DeleteArtistAlias(tx, artistID, uint(aliasID))
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка удаления псевдонима: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Псевдоним удалён"})
+}
+
+// MergeArtist godoc
+// @Summary Объединение артистов-дубликатов
+// @Description Переносит к артисту все песни, участие в песнях, альбомы и псевдонимы дубликата, сохраняет имя дубликата как псевдоним и удаляет дубликат. Кеш затронутых песен сбрасывается.
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID основного артиста"
+// @Param merge body models.ArtistMergeInput true "ID дубликата"
+// @Success 200 {object} models.ArtistMergeResult
+// @Failure 400 {object} models.ErrorResponse "Попытка объединить артиста с самим собой"
+// @Failure 404 {object} models.ErrorResponse "Артист не найден"
+// @Router /artists/{id}/merge [post]
+func MergeArtist(c *gin.Context) {
+	artistID, ok := parseArtistID(c)
+	if !ok {
+		return
+	}
+	var input models.ArtistMergeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON объединения: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Infof("Объединение артиста id: %d с дубликатом id: %d", artistID, input.DuplicateID)
+
+	var (
+		result  models.ArtistMergeResult
+		songIDs []uint
+	)
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		result, songIDs, err = services.MergeArtists(tx, artistID, input.DuplicateID)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка объединения артистов: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	invalidateSongs(songIDs)
+	services.RemoveArtistSuggestion(input.DuplicateID)
+	refreshSuggestions(songIDs...)
+	c.JSON(http.StatusOK, result)
+}
+
+// parseArtistID разбирает ID артиста из пути. При некорректном значении отвечает 404.
+func parseArtistID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrArtistNotFound.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,217 @@
+// internal/handlers/batch_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "bestEffort"
+
+	batchOpCreate = "create"
+	batchOpPatch  = "patch"
+	batchOpDelete = "delete"
+
+	maxBatchOperations = 500
+)
+
+var errBatchSkipped = errors.New("Операция отменена из-за ошибки в другой операции пакета")
+
+// BatchSongs godoc
+// @Summary Пакетные операции над песнями
+// @Description Выполняет список операций create (как POST /songs), patch (как PATCH /songs/{id}) и delete (как DELETE /songs/{id}). В режиме atomic (по умолчанию) все операции выполняются в одной транзакции: при ошибке ни одна не применяется. В режиме bestEffort каждая операция выполняется отдельно, а результат возвращается по каждой.
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param batch body models.BatchRequest true "Режим выполнения (atomic или bestEffort) и список операций (не более 500)"
+// @Success 200 {object} models.BatchResponse "Результаты по каждой операции"
+// @Failure 400 {object} models.ErrorResponse "Некорректный запрос"
+// @Failure 404 {object} models.BatchResponse "Атомарный пакет отменён: статус ответа совпадает со статусом первой ошибочной операции"
+// @Router /songs/batch [post]
+func BatchSongs(c *gin.Context) {
+	logger.Log.Info("Пакетная обработка песен")
+
+	var req models.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON пакета: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = batchModeAtomic
+	}
+	if req.Mode != batchModeAtomic && req.Mode != batchModeBestEffort {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Поле mode должно быть atomic или bestEffort"})
+		return
+	}
+	if len(req.Operations) == 0 || len(req.Operations) > maxBatchOperations {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Количество операций должно быть от 1 до " + strconv.Itoa(maxBatchOperations)})
+		return
+	}
+	logger.Log.Debugf("Режим пакета: %s, операций: %d", req.Mode, len(req.Operations))
+
+	results := make([]models.BatchItemResult, len(req.Operations))
+	details := make([]*models.SongDetail, len(req.Operations))
+	failed := false
+	for i, op := range req.Operations {
+		results[i] = models.BatchItemResult{Index: i, Op: op.Op}
+		if err := validateBatchOperation(op); err != nil {
+			setBatchError(&results[i], err, http.StatusBadRequest)
+			failed = true
+			continue
+		}
+		if op.Op == batchOpCreate {
+			// Внешний API опрашиваем до открытия транзакции, чтобы не держать её во время HTTP-запросов.
+			detail, err := services.FetchSongDetail(op.Group, op.Song)
+			if err != nil {
+				logger.Log.Errorf("Ошибка получения данных с внешнего API: %v", err)
+				setBatchError(&results[i], errors.New("Не удалось получить информацию о песне"), http.StatusInternalServerError)
+				failed = true
+				continue
+			}
+			details[i] = detail
+		}
+	}
+
+	if req.Mode == batchModeAtomic {
+		if failed {
+			respondBatchFailure(c, req.Mode, results)
+			return
+		}
+		err := database.WithTransaction(func(tx *gorm.DB) error {
+			for i, op := range req.Operations {
+				results[i] = models.BatchItemResult{Index: i, Op: op.Op}
+			}
+			for i, op := range req.Operations {
+				if err := applyBatchOperation(tx, op, details[i], &results[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Log.Errorf("Пакет отменён: %v", err)
+			respondBatchFailure(c, req.Mode, results)
+			return
+		}
+	} else {
+		for i, op := range req.Operations {
+			if results[i].Error != "" {
+				continue
+			}
+			database.WithTransaction(func(tx *gorm.DB) error {
+				return applyBatchOperation(tx, op, details[i], &results[i])
+			})
+		}
+	}
+
+	success := true
+	var changed []uint
+	for i, op := range req.Operations {
+		if results[i].Error != "" {
+			success = false
+			continue
+		}
+		if op.Op != batchOpCreate {
+			cache.Del("song:" + strconv.FormatUint(uint64(op.ID), 10))
+			changed = append(changed, op.ID)
+			if op.Op == batchOpPatch && op.Data.GroupName != nil && results[i].Song != nil {
+				renamed := renamedArtistSongs(results[i].Song.ArtistID)
+				invalidateSongs(renamed)
+				changed = append(changed, renamed...)
+			}
+		} else if results[i].Song != nil {
+			changed = append(changed, results[i].Song.ID)
+		}
+	}
+	refreshSuggestions(changed...)
+	logger.Log.Info("Пакет песен обработан")
+	c.JSON(http.StatusOK, models.BatchResponse{Mode: req.Mode, Success: success, Results: results})
+}
+
+func validateBatchOperation(op models.BatchOperation) error {
+	switch op.Op {
+	case batchOpCreate:
+		if op.Group == "" || op.Song == "" {
+			return services.ErrInvalidInput
+		}
+	case batchOpPatch:
+		if op.ID == 0 || op.Data == nil {
+			return errors.New("Для patch обязательны поля id и data")
+		}
+	case batchOpDelete:
+		if op.ID == 0 {
+			return errors.New("Для delete обязательно поле id")
+		}
+	default:
+		return errors.New("Неизвестная операция, допустимы create, patch, delete")
+	}
+	return nil
+}
+
+// applyBatchOperation выполняет одну операцию пакета и записывает её результат.
+func applyBatchOperation(tx *gorm.DB, op models.BatchOperation, detail *models.SongDetail, result *models.BatchItemResult) error {
+	var (
+		song   models.Song
+		err    error
+		status = http.StatusOK
+	)
+	switch op.Op {
+	case batchOpCreate:
+		song, err = services.CreateSong(tx, op.Group, op.Song, detail)
+		status = http.StatusCreated
+	case batchOpPatch:
+		song, err = services.UpdateSong(tx, op.ID, *op.Data)
+	case batchOpDelete:
+		err = services.DeleteSong(tx, op.ID)
+	}
+	if err != nil {
+		logger.Log.Errorf("Ошибка операции %d (%s): %v", result.Index, op.Op, err)
+		setBatchError(result, err, errorStatus(err))
+		return err
+	}
+
+	result.Status = status
+	if op.Op != batchOpDelete {
+		result.Song = &song
+	}
+	return nil
+}
+
+// respondBatchFailure отвечает на отменённый атомарный пакет: успешные до ошибки
+// операции помечаются как отменённые, статус ответа берётся у первой ошибки.
+func respondBatchFailure(c *gin.Context, mode string, results []models.BatchItemResult) {
+	status := 0
+	for i := range results {
+		if results[i].Error != "" {
+			if status == 0 {
+				status = results[i].Status
+			}
+			continue
+		}
+		results[i].Song = nil
+		setBatchError(&results[i], errBatchSkipped, http.StatusFailedDependency)
+	}
+	if status == 0 {
+		// Ошибка произошла не в операции, а например при фиксации транзакции.
+		status = http.StatusInternalServerError
+	}
+	c.JSON(status, models.BatchResponse{Mode: mode, Success: false, Results: results})
+}
+
+func setBatchError(result *models.BatchItemResult, err error, status int) {
+	result.Status = status
+	result.Error = err.Error()
+}
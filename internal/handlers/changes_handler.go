@@ -0,0 +1,59 @@
+// internal/handlers/changes_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetChanges godoc
+// @Summary Лента изменений каталога
+// @Description Возвращает события каталога (song.created, song.updated, song.deleted, artist.*) с номером больше since в порядке их фиксации, с полными данными сущности. События пишутся в той же транзакции, что и изменение, поэтому лента не теряет и не переставляет изменения. Для синхронизации передавайте в since значение nextSince из предыдущего ответа; since=0 — с начала. С параметром wait запрос ждёт новых событий до указанного числа секунд (long-poll).
+// @Tags changes
+// @Produce json
+// @Param since query int false "Номер последнего полученного события" default(0)
+// @Param limit query int false "Максимум событий в ответе (до 1000)" default(100)
+// @Param wait query int false "Сколько секунд ждать новых событий, если их нет (до 60)" default(0)
+// @Success 200 {object} models.ChangesResponse
+// @Failure 400 {object} models.ErrorResponse "Некорректный since"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /changes [get]
+func GetChanges(c *gin.Context) {
+	since, err := strconv.ParseUint(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный since, ожидается номер события"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultChangesLimit)))
+	if err != nil || limit < 1 {
+		limit = services.DefaultChangesLimit
+	}
+	if limit > services.MaxChangesLimit {
+		limit = services.MaxChangesLimit
+	}
+	waitSeconds, _ := strconv.Atoi(c.DefaultQuery("wait", "0"))
+	wait := time.Duration(waitSeconds) * time.Second
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > services.MaxChangesWait {
+		wait = services.MaxChangesWait
+	}
+	logger.Log.Debugf("Лента изменений: since=%d, limit=%d, wait=%s", since, limit, wait)
+
+	response, err := services.WaitForChanges(c.Request.Context(), database.DB, since, limit, wait)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения ленты изменений: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,91 @@
+// internal/handlers/credit_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetSongCredits godoc
+// @Summary Участники песни
+// @Description Возвращает исполнителей и авторов песни по порядку: основной исполнитель, приглашённые, композиторы, авторы текста, продюсеры.
+// @Tags credits
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.SongCredit
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/credits [get]
+func GetSongCredits(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Получение участников песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.First(&models.Song{}, songID).Error; err != nil {
+		logger.Log.Errorf("Песня не найдена: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return
+	}
+
+	credits, err := services.FindSongCredits(database.DB, songID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения участников: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, credits)
+}
+
+// PutSongCredits godoc
+// @Summary Замена участников песни
+// @Description Полностью заменяет список участников. Порядок в запросе задаёт позицию. Нужен хотя бы один основной исполнитель (primary), первый из них становится артистом песни. Артисты находятся по имени или создаются.
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param credits body []models.SongCreditInput true "Участники по порядку"
+// @Success 200 {array} models.SongCredit
+// @Failure 400 {object} models.ErrorResponse "Некорректная роль или нет основного исполнителя"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/credits [put]
+func PutSongCredits(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Замена участников песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var inputs []models.SongCreditInput
+	if err := c.ShouldBindJSON(&inputs); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON участников: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var credits []models.SongCredit
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		credits, err = services.ReplaceSongCredits(tx, songID, inputs)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка сохранения участников: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	cache.Del("song:" + id)
+	refreshSuggestions(songID)
+	logger.Log.Infof("Участники песни сохранены: %d", len(credits))
+	c.JSON(http.StatusOK, credits)
+}
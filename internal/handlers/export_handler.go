@@ -0,0 +1,169 @@
+// internal/handlers/export_handler.go
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	exportFormatJSON   = "json"
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+
+	// Через сколько строк сбрасывать буфер ответа клиенту.
+	exportFlushEvery = 100
+)
+
+var exportCSVHeader = []string{"id", "artistId", "group", "song", "releaseDate", "text", "link", "createdAt", "updatedAt"}
+
+// songExportRow — строка выгрузки: песня вместе с именем артиста из JOIN.
+type songExportRow struct {
+	models.Song
+	ArtistName string
+}
+
+// ExportSongs godoc
+// @Summary Экспорт каталога песен
+// @Description Выгружает все песни, подходящие под фильтры (те же, что у GET /songs), в формате JSON, CSV или NDJSON. Строки читаются из БД курсором и сразу отправляются клиенту, поэтому размер каталога не влияет на потребление памяти.
+// @Tags songs
+// @Produce json,text/csv,application/x-ndjson
+// @Param format query string false "Формат выгрузки: json (по умолчанию), csv или ndjson"
+// @Param group query string false "Название группы для фильтрации (регистр не важен, учитываются псевдонимы). Учитываются все участники песни, включая приглашённых"
+// @Param role query string false "Роль участника для фильтра group: primary, featured, composer, lyricist, producer"
+// @Param song query string false "Название песни для фильтрации (регистр не важен)"
+// @Param releaseDate query string false "Период релиза: YYYY, YYYY-MM или YYYY-MM-DD"
+// @Param releasedFrom query string false "Релиз не раньше периода (YYYY, YYYY-MM или YYYY-MM-DD)"
+// @Param releasedTo query string false "Релиз не позже периода (YYYY, YYYY-MM или YYYY-MM-DD)"
+// @Param text query string false "Фрагмент текста песни для поиска"
+// @Param link query string false "Полная URL ссылка для поиска (сравнивается и в канонической форме)"
+// @Param platform query string false "Платформы через запятую: youtube, spotify, apple_music, yandex_music и др."
+// @Param album query string false "Название альбома для фильтрации (регистр не важен)"
+// @Param albumId query int false "ID альбома"
+// @Param albumType query string false "Тип альбома: album, ep или single"
+// @Param genre query string false "Жанры через запятую, включая поджанры"
+// @Param genreMode query string false "Режим фильтра по жанрам: or (любой, по умолчанию) или and (все)"
+// @Param tag query string false "Теги через запятую"
+// @Param tagMode query string false "Режим фильтра по тегам: or (любой, по умолчанию) или and (все)"
+// @Success 200 {array} models.Song
+// @Failure 400 {object} models.ErrorResponse "Неизвестный формат"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /songs/export [get]
+func ExportSongs(c *gin.Context) {
+	format := c.DefaultQuery("format", exportFormatJSON)
+	logger.Log.Infof("Экспорт песен в формате %s", format)
+
+	var contentType, extension string
+	switch format {
+	case exportFormatJSON:
+		contentType, extension = "application/json", "json"
+	case exportFormatCSV:
+		contentType, extension = "text/csv; charset=utf-8", "csv"
+	case exportFormatNDJSON:
+		contentType, extension = "application/x-ndjson", "ndjson"
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Неизвестный формат экспорта, допустимы json, csv, ndjson"})
+		return
+	}
+
+	query := applySongFilters(database.DB.Model(&models.Song{}), c).
+		Select("songs.*, artists.name AS artist_name").
+		Joins("JOIN artists ON artists.id = songs.artist_id").
+		Order("songs.id")
+
+	rows, err := query.Rows()
+	if err != nil {
+		logger.Log.Errorf("Ошибка при выгрузке песен: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=songs-"+time.Now().Format("20060102")+"."+extension)
+	c.Status(http.StatusOK)
+
+	csvWriter := csv.NewWriter(c.Writer)
+	encoder := json.NewEncoder(c.Writer)
+	switch format {
+	case exportFormatCSV:
+		csvWriter.Write(exportCSVHeader)
+	case exportFormatJSON:
+		c.Writer.WriteString("[")
+	}
+
+	count := 0
+	for rows.Next() {
+		var row songExportRow
+		if err := database.DB.ScanRows(rows, &row); err != nil {
+			// Ответ уже начат, статус не изменить. Закрывающую скобку не пишем:
+			// выгрузка без неё не разбирается как JSON, и получатель видит, что
+			// она неполная, а не принимает обрезанный файл за целый.
+			logger.Log.Errorf("Ошибка чтения строки выгрузки, выгрузка прервана после %d песен: %v", count, err)
+			return
+		}
+		row.Artist = models.Artist{ID: row.ArtistID, Name: row.ArtistName}
+
+		switch format {
+		case exportFormatCSV:
+			err = csvWriter.Write(songCSVRecord(row))
+		case exportFormatJSON:
+			if count > 0 {
+				c.Writer.WriteString(",")
+			}
+			err = encoder.Encode(row.Song)
+		case exportFormatNDJSON:
+			err = encoder.Encode(row.Song)
+		}
+		if err != nil {
+			logger.Log.Errorf("Ошибка записи выгрузки клиенту: %v", err)
+			return
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			csvWriter.Flush()
+			c.Writer.Flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		logger.Log.Errorf("Ошибка курсора выгрузки, выгрузка прервана после %d песен: %v", count, err)
+		return
+	}
+
+	if format == exportFormatJSON {
+		c.Writer.WriteString("]")
+	}
+	csvWriter.Flush()
+	c.Writer.Flush()
+	logger.Log.Infof("Экспорт завершён, выгружено песен: %d", count)
+}
+
+// songCSVRecord разворачивает песню с артистом в плоскую строку CSV.
+// Колонки group, song, releaseDate, text и link совместимы с импортом.
+func songCSVRecord(row songExportRow) []string {
+	releaseDate := ""
+	if row.ReleaseDate != nil {
+		releaseDate = row.ReleaseDate.String()
+	}
+	return []string{
+		strconv.FormatUint(uint64(row.ID), 10),
+		strconv.FormatUint(uint64(row.ArtistID), 10),
+		row.ArtistName,
+		row.Song.Song,
+		releaseDate,
+		row.Text,
+		row.Link,
+		row.CreatedAt.Format(time.RFC3339),
+		row.UpdatedAt.Format(time.RFC3339),
+	}
+}
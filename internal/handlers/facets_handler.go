@@ -0,0 +1,104 @@
+// internal/handlers/facets_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultFacetLimit = 20
+	maxFacetLimit     = 100
+)
+
+// GetSongFacets godoc
+// @Summary Фасеты каталога
+// @Description Для текущего набора фильтров (тех же, что у GET /songs) возвращает общее число песен и количество песен по жанрам, тегам, артистам (включая приглашённых) и годам релиза. Используется для боковой панели фильтров.
+// @Tags songs
+// @Produce json
+// @Param group query string false "Название группы для фильтрации (регистр не важен, учитываются псевдонимы). Учитываются все участники песни, включая приглашённых"
+// @Param role query string false "Роль участника для фильтра group: primary, featured, composer, lyricist, producer"
+// @Param song query string false "Название песни для фильтрации (регистр не важен)"
+// @Param releaseDate query string false "Период релиза: YYYY, YYYY-MM или YYYY-MM-DD"
+// @Param releasedFrom query string false "Релиз не раньше периода (YYYY, YYYY-MM или YYYY-MM-DD)"
+// @Param releasedTo query string false "Релиз не позже периода (YYYY, YYYY-MM или YYYY-MM-DD)"
+// @Param text query string false "Фрагмент текста песни для поиска"
+// @Param link query string false "Полная URL ссылка для поиска (сравнивается и в канонической форме)"
+// @Param platform query string false "Платформы через запятую: youtube, spotify, apple_music, yandex_music и др."
+// @Param album query string false "Название альбома для фильтрации (регистр не важен)"
+// @Param albumId query int false "ID альбома"
+// @Param albumType query string false "Тип альбома: album, ep или single"
+// @Param genre query string false "Жанры через запятую, включая поджанры"
+// @Param genreMode query string false "Режим фильтра по жанрам: or (любой, по умолчанию) или and (все)"
+// @Param tag query string false "Теги через запятую"
+// @Param tagMode query string false "Режим фильтра по тегам: or (любой, по умолчанию) или and (все)"
+// @Param limit query int false "Максимум значений в каждом фасете" default(20)
+// @Success 200 {object} models.FacetsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /songs/facets [get]
+func GetSongFacets(c *gin.Context) {
+	logger.Log.Info("Подсчёт фасетов каталога")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultFacetLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultFacetLimit
+	}
+	if limit > maxFacetLimit {
+		limit = maxFacetLimit
+	}
+
+	// Подзапрос с ID песен, подходящих под фильтры. Собирается заново для каждого
+	// фасета, чтобы запросы не делили состояние.
+	filtered := func() *gorm.DB {
+		return applySongFilters(database.DB.Model(&models.Song{}), c).Select("songs.id")
+	}
+
+	facets := models.FacetsResponse{
+		Genres:  []models.FacetCount{},
+		Tags:    []models.FacetCount{},
+		Artists: []models.FacetCount{},
+		Years:   []models.YearFacet{},
+	}
+	queries := []*gorm.DB{
+		applySongFilters(database.DB.Model(&models.Song{}), c).Count(&facets.Total),
+		database.DB.Table("song_genres").
+			Select("genres.id, genres.name, COUNT(*) AS count").
+			Joins("JOIN genres ON genres.id = song_genres.genre_id").
+			Where("song_genres.song_id IN (?)", filtered()).
+			Group("genres.id, genres.name").Order("count DESC, genres.name").Limit(limit).
+			Scan(&facets.Genres),
+		database.DB.Table("song_tags").
+			Select("tags.id, tags.name, COUNT(*) AS count").
+			Joins("JOIN tags ON tags.id = song_tags.tag_id").
+			Where("song_tags.song_id IN (?)", filtered()).
+			Group("tags.id, tags.name").Order("count DESC, tags.name").Limit(limit).
+			Scan(&facets.Tags),
+		database.DB.Table("song_credits").
+			Select("artists.id, artists.name, COUNT(DISTINCT song_credits.song_id) AS count").
+			Joins("JOIN artists ON artists.id = song_credits.artist_id").
+			Where("song_credits.role IN ? AND song_credits.song_id IN (?)",
+				[]string{services.CreditRolePrimary, services.CreditRoleFeatured}, filtered()).
+			Group("artists.id, artists.name").Order("count DESC, artists.name").Limit(limit).
+			Scan(&facets.Artists),
+		database.DB.Table("songs").
+			Select("LEFT(release_date, 4)::int AS year, COUNT(*) AS count").
+			Where("release_date IS NOT NULL AND id IN (?)", filtered()).
+			Group("year").Order("year DESC").Limit(limit).
+			Scan(&facets.Years),
+	}
+	for _, query := range queries {
+		if query.Error != nil {
+			logger.Log.Errorf("Ошибка подсчёта фасетов: %v", query.Error)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: query.Error.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, facets)
+}
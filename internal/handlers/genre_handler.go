@@ -0,0 +1,206 @@
+// internal/handlers/genre_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetGenres godoc
+// @Summary Список жанров
+// @Description Возвращает жанры по алфавиту. С параметром tree=true жанры возвращаются деревом: в корне жанры без родителя, поджанры в поле children.
+// @Tags genres
+// @Produce json
+// @Param tree query bool false "Вернуть дерево жанров"
+// @Success 200 {array} models.Genre
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres [get]
+func GetGenres(c *gin.Context) {
+	logger.Log.Info("Получение списка жанров")
+	tree, _ := strconv.ParseBool(c.Query("tree"))
+
+	genres, err := services.ListGenres(database.DB, tree)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения жанров: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, genres)
+}
+
+// AddGenre godoc
+// @Summary Добавление жанра
+// @Description Создаёт жанр. Если передан parentId, жанр становится поджанром, и фильтр по родителю находит его песни.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body models.GenreInput true "Название и родительский жанр"
+// @Success 201 {object} models.Genre
+// @Failure 400 {object} models.ErrorResponse "Пустое название"
+// @Failure 404 {object} models.ErrorResponse "Родительский жанр не найден"
+// @Failure 409 {object} models.ErrorResponse "Жанр уже существует"
+// @Router /genres [post]
+func AddGenre(c *gin.Context) {
+	var input models.GenreInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON жанра: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Infof("Добавление жанра: %s", input.Name)
+
+	genre, err := services.CreateGenre(database.DB, input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка создания жанра: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, genre)
+}
+
+// PatchGenre godoc
+// @Summary Изменение жанра
+// @Description Переименовывает жанр или переносит его к другому родителю. parentId=0 делает жанр корневым. Вложить жанр в собственный поджанр нельзя.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path int true "ID жанра"
+// @Param genre body models.GenreUpdate true "Поля для обновления"
+// @Success 200 {object} models.Genre
+// @Failure 400 {object} models.ErrorResponse "Пустое название или цикл в иерархии"
+// @Failure 404 {object} models.ErrorResponse "Жанр не найден"
+// @Failure 409 {object} models.ErrorResponse "Жанр с таким названием уже существует"
+// @Router /genres/{id} [patch]
+func PatchGenre(c *gin.Context) {
+	logger.Log.Infof("Изменение жанра id: %s", c.Param("id"))
+	genreID, ok := parseGenreID(c)
+	if !ok {
+		return
+	}
+
+	var input models.GenreUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON жанра: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var genre models.Genre
+	var songIDs []uint
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		genre, songIDs, err = services.UpdateGenre(tx, genreID, input)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка изменения жанра: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	invalidateSongs(songIDs)
+	c.JSON(http.StatusOK, genre)
+}
+
+// DeleteGenre godoc
+// @Summary Удаление жанра
+// @Description Удаляет жанр. Поджанры становятся корневыми, у песен этот жанр снимается.
+// @Tags genres
+// @Produce json
+// @Param id path int true "ID жанра"
+// @Success 200 {object} models.MessageResponse "Жанр удалён"
+// @Failure 404 {object} models.ErrorResponse "Жанр не найден"
+// @Router /genres/{id} [delete]
+func DeleteGenre(c *gin.Context) {
+	logger.Log.Infof("Удаление жанра id: %s", c.Param("id"))
+	genreID, ok := parseGenreID(c)
+	if !ok {
+		return
+	}
+
+	var songIDs []uint
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		songIDs, err = services.DeleteGenre(tx, genreID)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка удаления жанра: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	invalidateSongs(songIDs)
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Жанр удалён"})
+}
+
+// PutSongGenres godoc
+// @Summary Замена жанров песни
+// @Description Полностью заменяет жанры песни. Пустой список снимает все жанры.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param genres body models.SongGenresInput true "ID жанров"
+// @Success 200 {array} models.Genre
+// @Failure 400 {object} models.ErrorResponse "Несуществующие жанры"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/genres [put]
+func PutSongGenres(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Замена жанров песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var input models.SongGenresInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON жанров: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var genres []models.Genre
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		genres, err = services.SetSongGenres(tx, songID, input.GenreIDs)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка сохранения жанров: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	cache.Del("song:" + id)
+	c.JSON(http.StatusOK, genres)
+}
+
+// parseGenreID разбирает ID жанра из пути. При некорректном значении отвечает 404.
+func parseGenreID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrGenreNotFound.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// invalidateSongs сбрасывает кеш карточек перечисленных песен.
+func invalidateSongs(songIDs []uint) {
+	if len(songIDs) == 0 {
+		return
+	}
+	keys := make([]string, len(songIDs))
+	for i, id := range songIDs {
+		keys[i] = "song:" + strconv.FormatUint(uint64(id), 10)
+	}
+	cache.Del(keys...)
+}
@@ -0,0 +1,309 @@
+// internal/handlers/graphql_handler.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+const (
+	// Глубже вложенность не нужна ни одному клиенту, а каждый уровень вида
+	// artist { songs { artist … } } умножает число загружаемых записей.
+	maxGraphQLDepth = 6
+	// Сколько полей и фрагментов может быть в запросе с учётом раскрытых
+	// фрагментов: не даёт обойти ограничение глубины сотней псевдонимов на
+	// одном уровне или цепочкой фрагментов, каждый из которых раскрывает
+	// следующий дважды.
+	maxGraphQLSelections = 300
+)
+
+var (
+	errGraphQLTooDeep    = errors.New("Слишком глубокий запрос GraphQL, допустимая вложенность — 6 уровней")
+	errGraphQLTooComplex = errors.New("Слишком сложный запрос GraphQL, допустимо не больше 300 полей и фрагментов")
+)
+
+// GraphQL godoc
+// @Summary GraphQL API каталога
+// @Description Выполняет запрос GraphQL. Запросы: song(id), songs(те же фильтры, что у GET /songs, sort, page, pageSize), artist(id), artists(name, page, pageSize). У песни можно запросить артиста, ссылки и страницу куплетов verses(page, pageSize, lang) — те же секции, что в GET /songs/{id}/text, из перевода, синхронизированного текста или текста песни; у артиста — псевдонимы и страницу песен songs(page, pageSize). Мутации addSong, patchSong и deleteSong работают как POST, PATCH и DELETE /songs. Артисты, ссылки и песни артистов загружаются пачками на весь ответ, без отдельного запроса на каждую песню. Запросы глубже 6 уровней или больше 300 полей и фрагментов отклоняются до выполнения. Ошибки возвращаются в поле errors со статусом 200.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body models.GraphQLRequest true "Запрос GraphQL и его переменные"
+// @Success 200 {object} models.GraphQLResponse
+// @Failure 400 {object} models.ErrorResponse "Некорректное тело запроса"
+// @Router /graphql [post]
+func GraphQL(c *gin.Context) {
+	var request models.GraphQLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге запроса GraphQL: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Debugf("Запрос GraphQL: %s", request.OperationName)
+
+	if err := checkGraphQLLimits(request.Query); err != nil {
+		logger.Log.Warnf("Запрос GraphQL отклонён: %v", err)
+		c.JSON(http.StatusOK, models.GraphQLResponse{Errors: []models.GraphQLError{{Message: err.Error()}}})
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         graphQLSchema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		Context:        withLoaders(c.Request.Context()),
+	})
+	if result.HasErrors() {
+		logger.Log.Debugf("Ошибки выполнения GraphQL: %v", result.Errors)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// checkGraphQLLimits проверяет глубину и число полей запроса до его выполнения.
+// Синтаксические ошибки здесь не сообщаются: их вернёт graphql.Do.
+func checkGraphQLLimits(query string) error {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+	walker := &graphQLLimitWalker{fragments: map[string]*ast.FragmentDefinition{}, visiting: map[string]bool{}}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			walker.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	for _, definition := range document.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok {
+			if err := walker.walk(operation.SelectionSet, 1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type graphQLLimitWalker struct {
+	fragments map[string]*ast.FragmentDefinition
+	// Фрагменты на текущем пути: циклические ссылки не раскрываются повторно.
+	visiting   map[string]bool
+	selections int
+}
+
+func (w *graphQLLimitWalker) walk(set *ast.SelectionSet, depth int) error {
+	if set == nil {
+		return nil
+	}
+	for _, selection := range set.Selections {
+		if w.selections++; w.selections > maxGraphQLSelections {
+			return errGraphQLTooComplex
+		}
+		switch s := selection.(type) {
+		case *ast.Field:
+			// Интроспекция обходит конечную схему, а не каталог, и у неё
+			// своя глубокая вложенность ofType.
+			if s.Name != nil && strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			if depth > maxGraphQLDepth {
+				return errGraphQLTooDeep
+			}
+			if err := w.walk(s.SelectionSet, depth+1); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			if err := w.walk(s.SelectionSet, depth); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			if s.Name == nil {
+				continue
+			}
+			fragment, ok := w.fragments[s.Name.Value]
+			if !ok || w.visiting[s.Name.Value] {
+				continue
+			}
+			w.visiting[s.Name.Value] = true
+			err := w.walk(fragment.SelectionSet, depth)
+			delete(w.visiting, s.Name.Value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// batchLoader собирает ключи, запрошенные резолверами одного уровня ответа, и
+// загружает их одним запросом. Резолвер получает отложенное значение (thunk):
+// graphql-go вычисляет их после обхода всего уровня, поэтому к моменту первого
+// вычисления все ключи уровня уже собраны. Загруженное кешируется на время запроса.
+type batchLoader[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(keys []K) (map[K]V, error)
+	pending []K
+	loaded  map[K]V
+}
+
+func newBatchLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{fetch: fetch, loaded: map[K]V{}}
+}
+
+// Load откладывает загрузку значения по ключу до вычисления thunk.
+func (l *batchLoader[K, V]) Load(key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.loaded[key]; !ok && !slices.Contains(l.pending, key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if value, ok := l.loaded[key]; ok {
+			return value, nil
+		}
+		keys := l.pending
+		l.pending = nil
+		values, err := l.fetch(keys)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			// Отсутствующие ключи тоже запоминаем, чтобы не запрашивать их снова.
+			l.loaded[k] = values[k]
+		}
+		return l.loaded[key], nil
+	}
+}
+
+// graphQLLoaders — загрузчики одного запроса GraphQL.
+type graphQLLoaders struct {
+	artists     *batchLoader[uint, *models.Artist]
+	aliases     *batchLoader[uint, []string]
+	links       *batchLoader[uint, []models.SongLink]
+	lyrics      *batchLoader[uint, songLyricsSources]
+	artistSongs *batchLoader[artistSongsKey, []models.Song]
+}
+
+// artistSongsKey — страница песен артиста.
+type artistSongsKey struct {
+	artistID uint
+	page     int
+	pageSize int
+}
+
+type loadersKey struct{}
+
+// withLoaders создаёт загрузчики запроса. Они запрашивают БД с контекстом
+// запроса, поэтому после отмены запроса новые запросы к БД не выполняются.
+func withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &graphQLLoaders{
+		artists: newBatchLoader(func(ids []uint) (map[uint]*models.Artist, error) {
+			return loadArtists(ctx, ids)
+		}),
+		aliases: newBatchLoader(func(ids []uint) (map[uint][]string, error) {
+			return loadArtistAliases(ctx, ids)
+		}),
+		links: newBatchLoader(func(ids []uint) (map[uint][]models.SongLink, error) {
+			return loadSongLinks(ctx, ids)
+		}),
+		lyrics: newBatchLoader(func(ids []uint) (map[uint]songLyricsSources, error) {
+			return loadSongLyrics(ctx, ids)
+		}),
+		artistSongs: newBatchLoader(func(keys []artistSongsKey) (map[artistSongsKey][]models.Song, error) {
+			return loadArtistSongs(ctx, keys)
+		}),
+	})
+}
+
+func loadersFrom(ctx context.Context) *graphQLLoaders {
+	return ctx.Value(loadersKey{}).(*graphQLLoaders)
+}
+
+func loadArtists(ctx context.Context, ids []uint) (map[uint]*models.Artist, error) {
+	var artists []models.Artist
+	if err := database.DB.WithContext(ctx).Where("id IN ?", ids).Find(&artists).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]*models.Artist, len(artists))
+	for i := range artists {
+		result[artists[i].ID] = &artists[i]
+	}
+	return result, nil
+}
+
+func loadArtistAliases(ctx context.Context, artistIDs []uint) (map[uint][]string, error) {
+	var aliases []models.ArtistAlias
+	if err := database.DB.WithContext(ctx).Where("artist_id IN ?", artistIDs).Order("name").Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]string, len(artistIDs))
+	for _, id := range artistIDs {
+		result[id] = []string{}
+	}
+	for _, alias := range aliases {
+		result[alias.ArtistID] = append(result[alias.ArtistID], alias.Name)
+	}
+	return result, nil
+}
+
+func loadSongLinks(ctx context.Context, songIDs []uint) (map[uint][]models.SongLink, error) {
+	var links []models.SongLink
+	if err := database.DB.WithContext(ctx).Where("song_id IN ?", songIDs).Order("id").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]models.SongLink, len(songIDs))
+	for _, id := range songIDs {
+		result[id] = []models.SongLink{}
+	}
+	for _, link := range links {
+		result[link.SongID] = append(result[link.SongID], link)
+	}
+	return result, nil
+}
+
+// loadArtistSongs загружает страницы песен артистов: по одному запросу на каждый
+// размер и номер страницы, а не на каждого артиста. Нумерация песен внутри
+// артиста считается оконной функцией, поэтому из БД читается только страница.
+func loadArtistSongs(ctx context.Context, keys []artistSongsKey) (map[artistSongsKey][]models.Song, error) {
+	type window struct{ page, pageSize int }
+	artistIDs := make(map[window][]uint)
+	for _, key := range keys {
+		w := window{key.page, key.pageSize}
+		artistIDs[w] = append(artistIDs[w], key.artistID)
+	}
+
+	result := make(map[artistSongsKey][]models.Song, len(keys))
+	for _, key := range keys {
+		result[key] = []models.Song{}
+	}
+	for w, ids := range artistIDs {
+		var songs []models.Song
+		err := database.DB.WithContext(ctx).Raw(`SELECT * FROM (
+				SELECT songs.*, ROW_NUMBER() OVER (PARTITION BY artist_id ORDER BY id) AS artist_row
+				FROM songs WHERE artist_id IN ?
+			) AS ranked WHERE artist_row > ? AND artist_row <= ? ORDER BY artist_id, artist_row`,
+			ids, (w.page-1)*w.pageSize, w.page*w.pageSize).Scan(&songs).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, song := range songs {
+			key := artistSongsKey{artistID: song.ArtistID, page: w.page, pageSize: w.pageSize}
+			result[key] = append(result[key], song)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,393 @@
+// internal/handlers/graphql_schema.go
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultGraphQLPageSize = 10
+	defaultVersesPageSize  = 5
+	maxGraphQLPageSize     = 100
+)
+
+// graphQLArgs — аргументы поля GraphQL как источник параметров фильтра песен,
+// чтобы songs фильтровался тем же кодом, что и GET /songs.
+type graphQLArgs map[string]interface{}
+
+func (a graphQLArgs) Query(key string) string {
+	switch value := a[key].(type) {
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	}
+	return ""
+}
+
+func (a graphQLArgs) QueryArray(key string) []string {
+	switch value := a[key].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var result []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// pageArgs возвращает страницу и её размер из аргументов page и pageSize.
+func pageArgs(args map[string]interface{}, defaultSize int) (page, pageSize int) {
+	page, _ = args["page"].(int)
+	pageSize, _ = args["pageSize"].(int)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSize
+	}
+	if pageSize > maxGraphQLPageSize {
+		pageSize = maxGraphQLPageSize
+	}
+	return page, pageSize
+}
+
+func songSource(source interface{}) models.Song {
+	if song, ok := source.(*models.Song); ok {
+		return *song
+	}
+	return source.(models.Song)
+}
+
+func artistSource(source interface{}) *models.Artist {
+	if artist, ok := source.(models.Artist); ok {
+		return &artist
+	}
+	return source.(*models.Artist)
+}
+
+func pageArguments(defaultSize int) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"page":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1, Description: "Номер страницы"},
+		"pageSize": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultSize, Description: "Размер страницы (до 100)"},
+	}
+}
+
+// versesArguments — аргументы verses: страница и язык текста.
+func versesArguments() graphql.FieldConfigArgument {
+	args := pageArguments(defaultVersesPageSize)
+	args["lang"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "Язык текста; если перевода нет, возвращается оригинал"}
+	return args
+}
+
+var graphQLLinkType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Link",
+	Fields: graphql.Fields{
+		"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"platform": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"kind":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"url":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var graphQLVersePageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "VersePage",
+	Description: "Страница куплетов, как поле verses в GET /songs/{id}/text: секции текста без меток вида [Chorus]",
+	Fields: graphql.Fields{
+		"verses":   &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+		"page":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"pageSize": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"total":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Всего куплетов"},
+		"lang":     &graphql.Field{Type: graphql.String, Description: "Язык текста"},
+	},
+})
+
+var graphQLArtistType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Artist",
+	Fields: graphql.Fields{
+		"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"aliases": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).aliases.Load(artistSource(p.Source).ID), nil
+			},
+		},
+		"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var graphQLSongType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Song",
+	Fields: graphql.Fields{
+		"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"song": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"releaseDate": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Дата релиза: YYYY, YYYY-MM или YYYY-MM-DD",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if date := songSource(p.Source).ReleaseDate; date != nil {
+					return date.String(), nil
+				}
+				return nil, nil
+			},
+		},
+		"text": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"link": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"lang": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"artist": &graphql.Field{
+			Type: graphql.NewNonNull(graphQLArtistType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).artists.Load(songSource(p.Source).ArtistID), nil
+			},
+		},
+		"links": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLLinkType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loadersFrom(p.Context).links.Load(songSource(p.Source).ID), nil
+			},
+		},
+		"verses": &graphql.Field{
+			Type:        graphql.NewNonNull(graphQLVersePageType),
+			Description: "Страница куплетов текста: перевода на языке lang, синхронизированного текста или текста песни",
+			Args:        versesArguments(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				song := songSource(p.Source)
+				page, pageSize := pageArgs(p.Args, defaultVersesPageSize)
+				lang, _ := p.Args["lang"].(string)
+				load := loadersFrom(p.Context).lyrics.Load(song.ID)
+				return func() (interface{}, error) {
+					sources, err := load()
+					if err != nil {
+						return nil, err
+					}
+					text, textLang := localizedLyrics(song, sources.(songLyricsSources), services.PreferredLangs(lang, ""))
+					verses, total := versePage(text, page, pageSize)
+					return map[string]interface{}{
+						"verses":   verses,
+						"page":     page,
+						"pageSize": pageSize,
+						"total":    total,
+						"lang":     textLang,
+					}, nil
+				}, nil
+			},
+		},
+		"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+var graphQLSongUpdateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SongUpdateInput",
+	Description: "Изменяемые поля песни; непереданные поля не меняются",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"group":       &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Новое имя основного исполнителя, меняется во всех его песнях"},
+		"moveTo":      &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Перенос песни к другому артисту, «A feat. B» пересобирает участников"},
+		"song":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"releaseDate": &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Новая дата релиза; пустая строка сбрасывает дату"},
+		"text":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"link":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"lang":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// songFilterArguments — аргументы songs, повторяющие query-параметры GET /songs.
+func songFilterArguments() graphql.FieldConfigArgument {
+	args := pageArguments(defaultGraphQLPageSize)
+	for _, name := range []string{"group", "role", "song", "releaseDate", "releasedFrom", "releasedTo",
+		"text", "link", "album", "albumType", "genreMode", "tagMode", "sort"} {
+		args[name] = &graphql.ArgumentConfig{Type: graphql.String}
+	}
+	for _, name := range []string{"platform", "genre", "tag"} {
+		args[name] = &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))}
+	}
+	args["albumId"] = &graphql.ArgumentConfig{Type: graphql.Int}
+	return args
+}
+
+var graphQLQueryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: graphql.Fields{
+		"song": &graphql.Field{
+			Type: graphQLSongType,
+			Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var song models.Song
+				err := database.DB.WithContext(p.Context).First(&song, p.Args["id"]).Error
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, nil
+				}
+				return song, err
+			},
+		},
+		"songs": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLSongType))),
+			Description: "Список песен с теми же фильтрами и сортировкой, что у GET /songs",
+			Args:        songFilterArguments(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				args := graphQLArgs(p.Args)
+				query := applySongFilters(database.DB.WithContext(p.Context).Model(&models.Song{}), args)
+				query, err := sortSongs(query, args.Query("sort"))
+				if err != nil {
+					return nil, err
+				}
+				page, pageSize := pageArgs(p.Args, defaultGraphQLPageSize)
+				songs := []models.Song{}
+				err = query.Limit(pageSize).Offset((page - 1) * pageSize).Find(&songs).Error
+				return songs, err
+			},
+		},
+		"artist": &graphql.Field{
+			Type: graphQLArtistType,
+			Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var artist models.Artist
+				err := database.DB.WithContext(p.Context).First(&artist, p.Args["id"]).Error
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, nil
+				}
+				return artist, err
+			},
+		},
+		"artists": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLArtistType))),
+			Description: "Список артистов по алфавиту; name ищет по имени и псевдонимам",
+			Args: func() graphql.FieldConfigArgument {
+				args := pageArguments(defaultGraphQLPageSize)
+				args["name"] = &graphql.ArgumentConfig{Type: graphql.String}
+				return args
+			}(),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				query := database.DB.WithContext(p.Context).Model(&models.Artist{})
+				if name, _ := p.Args["name"].(string); name != "" {
+					query = query.Where("id IN (?)", artistsMatching(name))
+				}
+				page, pageSize := pageArgs(p.Args, defaultGraphQLPageSize)
+				artists := []models.Artist{}
+				err := query.Order("name, id").Limit(pageSize).Offset((page - 1) * pageSize).Find(&artists).Error
+				return artists, err
+			},
+		},
+	},
+})
+
+var graphQLMutationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Mutation",
+	Fields: graphql.Fields{
+		"addSong": &graphql.Field{
+			Type:        graphql.NewNonNull(graphQLSongType),
+			Description: "Добавляет песню с обогащением через внешний API, как POST /songs",
+			Args: graphql.FieldConfigArgument{
+				"group": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"song":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				group, title := p.Args["group"].(string), p.Args["song"].(string)
+				logger.Log.Infof("GraphQL: добавление песни %s - %s", group, title)
+				detail, err := services.FetchSongDetail(group, title)
+				if err != nil {
+					logger.Log.Errorf("Ошибка получения данных с внешнего API: %v", err)
+					return nil, errors.New("Не удалось получить информацию о песне")
+				}
+				return createSong(group, title, detail)
+			},
+		},
+		"patchSong": &graphql.Field{
+			Type:        graphql.NewNonNull(graphQLSongType),
+			Description: "Изменяет переданные поля песни, как PATCH /songs/{id}",
+			Args: graphql.FieldConfigArgument{
+				"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLSongUpdateInput)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id := p.Args["id"].(int)
+				logger.Log.Infof("GraphQL: обновление песни id: %d", id)
+				input, err := songUpdateFromArgs(p.Args["input"].(map[string]interface{}))
+				if err != nil {
+					return nil, err
+				}
+				return updateSong(uint(id), input)
+			},
+		},
+		"deleteSong": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Удаляет песню, как DELETE /songs/{id}",
+			Args:        graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				id := p.Args["id"].(int)
+				logger.Log.Infof("GraphQL: удаление песни id: %d", id)
+				if err := deleteSong(uint(id)); err != nil {
+					return false, err
+				}
+				return true, nil
+			},
+		},
+	},
+})
+
+// songUpdateFromArgs собирает SongUpdate из полей SongUpdateInput.
+func songUpdateFromArgs(fields map[string]interface{}) (models.SongUpdate, error) {
+	var input models.SongUpdate
+	targets := map[string]**string{
+		"group":  &input.GroupName,
+		"moveTo": &input.MoveTo,
+		"song":   &input.Song,
+		"text":   &input.Text,
+		"link":   &input.Link,
+		"lang":   &input.Lang,
+	}
+	for name, target := range targets {
+		if value, ok := fields[name].(string); ok {
+			*target = &value
+		}
+	}
+	if value, ok := fields["releaseDate"].(string); ok {
+		date, err := models.ParsePartialDateUpdate(value)
+		if err != nil {
+			return input, err
+		}
+		input.ReleaseDate = &date
+	}
+	return input, nil
+}
+
+var graphQLSchema graphql.Schema
+
+func init() {
+	// Песни артиста добавляются здесь, а не в описании типа: типы Artist и Song
+	// ссылаются друг на друга.
+	graphQLArtistType.AddFieldConfig("songs", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLSongType))),
+		Description: "Песни, где артист основной исполнитель, по порядку добавления",
+		Args:        pageArguments(defaultGraphQLPageSize),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			page, pageSize := pageArgs(p.Args, defaultGraphQLPageSize)
+			key := artistSongsKey{artistID: artistSource(p.Source).ID, page: page, pageSize: pageSize}
+			return loadersFrom(p.Context).artistSongs.Load(key), nil
+		},
+	})
+	var err error
+	graphQLSchema, err = graphql.NewSchema(graphql.SchemaConfig{
+		Query:    graphQLQueryType,
+		Mutation: graphQLMutationType,
+	})
+	if err != nil {
+		panic("некорректная схема GraphQL: " + err.Error())
+	}
+}
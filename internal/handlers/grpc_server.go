@@ -0,0 +1,342 @@
+// internal/handlers/grpc_server.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+	"songs/pkg/songspb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultGRPCPageSize = 10
+	// Больше за один ответ не отдаём: для выгрузки есть потоковый StreamSongs.
+	maxGRPCPageSize = 1000
+	// Размер страницы куплетов в GetVerses: те же значения, что у GET /songs/{id}/text и GraphQL.
+	defaultGRPCVersesPageSize = 5
+	maxGRPCVersesPageSize     = 100
+	// Сколько песен StreamSongs читает из БД за один запрос.
+	grpcStreamBatch = 500
+)
+
+// songsGRPCServer реализует songs.v1.SongsService поверх тех же фильтров и
+// операций, что и REST-обработчики.
+type songsGRPCServer struct {
+	songspb.UnimplementedSongsServiceServer
+}
+
+// NewGRPCServer создаёт gRPC-сервер каталога с зарегистрированным SongsService.
+func NewGRPCServer() *grpc.Server {
+	server := grpc.NewServer()
+	songspb.RegisterSongsServiceServer(server, &songsGRPCServer{})
+	return server
+}
+
+func (s *songsGRPCServer) ListSongs(ctx context.Context, req *songspb.ListSongsRequest) (*songspb.ListSongsResponse, error) {
+	logger.Log.Info("gRPC: получение списка песен")
+	query := applySongFilters(database.DB.WithContext(ctx).Scopes(preloadSongRelations).Model(&models.Song{}),
+		songFilterValues(req.GetFilter()))
+	query, err := sortSongs(query, req.GetSort())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, pageSize := int(req.GetPage()), int(req.GetPageSize())
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultGRPCPageSize
+	}
+	if pageSize > maxGRPCPageSize {
+		pageSize = maxGRPCPageSize
+	}
+	var songs []models.Song
+	if err := query.Limit(pageSize).Offset((page - 1) * pageSize).Find(&songs).Error; err != nil {
+		logger.Log.Errorf("Ошибка при получении песен: %v", err)
+		return nil, grpcError(err)
+	}
+	response := &songspb.ListSongsResponse{Songs: make([]*songspb.Song, len(songs))}
+	for i := range songs {
+		response.Songs[i] = songToProto(songs[i])
+	}
+	return response, nil
+}
+
+func (s *songsGRPCServer) StreamSongs(req *songspb.StreamSongsRequest, stream grpc.ServerStreamingServer[songspb.Song]) error {
+	logger.Log.Info("gRPC: выгрузка песен потоком")
+	ctx := stream.Context()
+	filter := songFilterValues(req.GetFilter())
+	var lastID uint
+	count := 0
+	for {
+		// Пачки выбираются по ID после последнего отправленного, а не через OFFSET:
+		// каждая следующая пачка читается так же быстро, как первая.
+		var songs []models.Song
+		err := applySongFilters(database.DB.WithContext(ctx).Scopes(preloadSongRelations).Model(&models.Song{}), filter).
+			Where("songs.id > ?", lastID).Order("songs.id").Limit(grpcStreamBatch).
+			Find(&songs).Error
+		if err != nil {
+			logger.Log.Errorf("Ошибка выгрузки песен: %v", err)
+			return grpcError(err)
+		}
+		for i := range songs {
+			if err := stream.Send(songToProto(songs[i])); err != nil {
+				return err
+			}
+		}
+		count += len(songs)
+		if len(songs) < grpcStreamBatch {
+			logger.Log.Infof("gRPC: выгрузка завершена, песен: %d", count)
+			return nil
+		}
+		lastID = songs[len(songs)-1].ID
+	}
+}
+
+func (s *songsGRPCServer) GetSong(ctx context.Context, req *songspb.GetSongRequest) (*songspb.Song, error) {
+	logger.Log.Infof("gRPC: получение песни id: %d", req.GetId())
+	song, err := findGRPCSong(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return songToProto(song), nil
+}
+
+func (s *songsGRPCServer) CreateSong(ctx context.Context, req *songspb.CreateSongRequest) (*songspb.Song, error) {
+	logger.Log.Infof("gRPC: добавление песни %s - %s", req.GetGroup(), req.GetSong())
+	if req.GetGroup() == "" || req.GetSong() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Поля group и song обязательны")
+	}
+	detail, err := services.FetchSongDetail(req.GetGroup(), req.GetSong())
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения данных с внешнего API: %v", err)
+		return nil, status.Error(codes.Unavailable, "Не удалось получить информацию о песне")
+	}
+	song, err := createSong(req.GetGroup(), req.GetSong(), detail)
+	if err != nil {
+		logger.Log.Errorf("Ошибка создания записи в БД о песне: %v", err)
+		return nil, grpcError(err)
+	}
+	return songToProto(song), nil
+}
+
+func (s *songsGRPCServer) UpdateSong(ctx context.Context, req *songspb.UpdateSongRequest) (*songspb.Song, error) {
+	logger.Log.Infof("gRPC: обновление песни id: %d", req.GetId())
+	input := models.SongUpdate{
+		GroupName: req.Group,
+		MoveTo:    req.MoveTo,
+		Song:      req.Song,
+		Text:      req.Text,
+		Link:      req.Link,
+		Lang:      req.Lang,
+	}
+	if req.ReleaseDate != nil {
+		date, err := models.ParsePartialDateUpdate(req.GetReleaseDate())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		input.ReleaseDate = &date
+	}
+	song, err := updateSong(uint(req.GetId()), input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка обновления песни: %v", err)
+		return nil, grpcError(err)
+	}
+	return songToProto(song), nil
+}
+
+func (s *songsGRPCServer) DeleteSong(ctx context.Context, req *songspb.DeleteSongRequest) (*songspb.DeleteSongResponse, error) {
+	logger.Log.Infof("gRPC: удаление песни id: %d", req.GetId())
+	if err := deleteSong(uint(req.GetId())); err != nil {
+		logger.Log.Errorf("Ошибка удаления песни: %v", err)
+		return nil, grpcError(err)
+	}
+	return &songspb.DeleteSongResponse{}, nil
+}
+
+func (s *songsGRPCServer) GetVerses(ctx context.Context, req *songspb.GetVersesRequest) (*songspb.GetVersesResponse, error) {
+	logger.Log.Infof("gRPC: получение куплетов песни id: %d", req.GetId())
+	var song models.Song
+	if err := database.DB.WithContext(ctx).First(&song, req.GetId()).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = services.ErrSongNotFound
+		}
+		return nil, grpcError(err)
+	}
+	page, pageSize := int(req.GetPage()), int(req.GetPageSize())
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultGRPCVersesPageSize
+	}
+	if pageSize > maxGRPCVersesPageSize {
+		pageSize = maxGRPCVersesPageSize
+	}
+	sources, err := loadSongLyrics(ctx, []uint{song.ID})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	text, lang := localizedLyrics(song, sources[song.ID], services.PreferredLangs(req.GetLang(), ""))
+	verses, total := versePage(text, page, pageSize)
+	return &songspb.GetVersesResponse{
+		Verses:   verses,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+		Total:    int32(total),
+		Lang:     lang,
+	}, nil
+}
+
+func (s *songsGRPCServer) GetArtist(ctx context.Context, req *songspb.GetArtistRequest) (*songspb.Artist, error) {
+	logger.Log.Infof("gRPC: получение артиста id: %d", req.GetId())
+	artist, err := services.FindArtist(database.DB.WithContext(ctx), uint(req.GetId()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return artistToProto(artist), nil
+}
+
+func (s *songsGRPCServer) LookupArtist(ctx context.Context, req *songspb.LookupArtistRequest) (*songspb.Artist, error) {
+	logger.Log.Infof("gRPC: поиск артиста по имени: %s", req.GetName())
+	db := database.DB.WithContext(ctx)
+	found, err := services.FindArtistByName(db, req.GetName())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = services.ErrArtistNotFound
+	}
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	artist, err := services.FindArtist(db, found.ID)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return artistToProto(artist), nil
+}
+
+func findGRPCSong(ctx context.Context, id uint64) (models.Song, error) {
+	var song models.Song
+	err := database.DB.WithContext(ctx).Scopes(preloadSongRelations).First(&song, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = services.ErrSongNotFound
+	}
+	if err != nil {
+		return song, grpcError(err)
+	}
+	return song, nil
+}
+
+// grpcError переводит ошибку сервисного слоя в статус gRPC по тем же правилам,
+// по которым errorStatus выбирает HTTP-статус.
+func grpcError(err error) error {
+	code := codes.Internal
+	switch errorStatus(err) {
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	}
+	return status.Error(code, err.Error())
+}
+
+// queryValues — параметры фильтра из url.Values, когда фильтры приходят не из
+// HTTP-запроса.
+type queryValues url.Values
+
+func (v queryValues) Query(key string) string {
+	return url.Values(v).Get(key)
+}
+
+func (v queryValues) QueryArray(key string) []string {
+	return v[key]
+}
+
+// songFilterValues переводит SongFilter в query-параметры GET /songs.
+func songFilterValues(filter *songspb.SongFilter) queryValues {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	set("group", filter.GetGroup())
+	set("role", filter.GetRole())
+	set("song", filter.GetSong())
+	set("releaseDate", filter.GetReleaseDate())
+	set("releasedFrom", filter.GetReleasedFrom())
+	set("releasedTo", filter.GetReleasedTo())
+	set("text", filter.GetText())
+	set("link", filter.GetLink())
+	set("album", filter.GetAlbum())
+	set("albumType", filter.GetAlbumType())
+	set("genreMode", filter.GetGenreMode())
+	set("tagMode", filter.GetTagMode())
+	if filter != nil && filter.AlbumId != nil {
+		values.Set("albumId", strconv.FormatUint(filter.GetAlbumId(), 10))
+	}
+	values["platform"] = filter.GetPlatform()
+	values["genre"] = filter.GetGenre()
+	values["tag"] = filter.GetTag()
+	return queryValues(values)
+}
+
+func songToProto(song models.Song) *songspb.Song {
+	result := &songspb.Song{
+		Id:        uint64(song.ID),
+		ArtistId:  uint64(song.ArtistID),
+		Song:      song.Song,
+		Text:      song.Text,
+		Link:      song.Link,
+		Lang:      song.Lang,
+		CreatedAt: timestamppb.New(song.CreatedAt),
+		UpdatedAt: timestamppb.New(song.UpdatedAt),
+	}
+	if song.ReleaseDate != nil {
+		result.ReleaseDate = song.ReleaseDate.String()
+	}
+	if song.Artist.ID != 0 {
+		result.Artist = artistToProto(song.Artist)
+	}
+	for _, link := range song.Links {
+		result.Links = append(result.Links, &songspb.Link{
+			Id:       uint64(link.ID),
+			Platform: link.Platform,
+			Kind:     link.Kind,
+			Url:      link.URL,
+		})
+	}
+	return result
+}
+
+func artistToProto(artist models.Artist) *songspb.Artist {
+	result := &songspb.Artist{
+		Id:        uint64(artist.ID),
+		Name:      artist.Name,
+		CreatedAt: timestamppb.New(artist.CreatedAt),
+		UpdatedAt: timestamppb.New(artist.UpdatedAt),
+	}
+	for _, alias := range artist.Aliases {
+		result.Aliases = append(result.Aliases, alias.Name)
+	}
+	return result
+}
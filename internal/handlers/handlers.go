@@ -2,19 +2,22 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"songs/database"
 	"songs/internal/cache"
 	"songs/internal/logger"
 	"songs/internal/models"
+	"songs/internal/search"
 	"songs/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetSongs godoc
@@ -23,68 +26,280 @@ import (
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param group query string false "Название группы для фильтрации (регистр не важен)"
+// @Param group query string false "Название группы для фильтрации (регистр не важен, учитываются псевдонимы). Учитываются все участники песни, включая приглашённых"
+// @Param role query string false "Роль участника для фильтра group: primary, featured, composer, lyricist, producer"
 // @Param song query string false "Название песни для фильтрации (регистр не важен)"
-// @Param releaseDate query string false "Дата релиза для фильтрации(в формате YYYY-MM-DD)"
+// @Param releaseDate query string false "Период релиза: YYYY, YYYY-MM или YYYY-MM-DD. Находит песни с датой внутри периода; песни с менее точной датой (только год) под фильтр по месяцу не попадают"
+// @Param releasedFrom query string false "Релиз не раньше периода (YYYY, YYYY-MM или YYYY-MM-DD)"
+// @Param releasedTo query string false "Релиз не позже периода (YYYY, YYYY-MM или YYYY-MM-DD)"
 // @Param text query string false "Фрагмент текста песни для поиска"
-// @Param link query string false "Полная URL ссылка для поиска"
+// @Param link query string false "Полная URL ссылка для поиска (сравнивается и в канонической форме)"
+// @Param platform query string false "Платформы через запятую: youtube, spotify, apple_music, yandex_music и др."
+// @Param album query string false "Название альбома для фильтрации (регистр не важен)"
+// @Param albumId query int false "ID альбома"
+// @Param albumType query string false "Тип альбома: album, ep или single"
+// @Param genre query string false "Жанры через запятую, включая поджанры"
+// @Param genreMode query string false "Режим фильтра по жанрам: or (любой, по умолчанию) или and (все)"
+// @Param tag query string false "Теги через запятую"
+// @Param tagMode query string false "Режим фильтра по тегам: or (любой, по умолчанию) или and (все)"
+// @Param sort query string false "Сортировка: popularity — сначала популярные, releaseDate / -releaseDate — по дате релиза (песни без даты в конце). По умолчанию по ID"
 // @Param page query int false "Номер страницы (по умолчанию 1)"
 // @Param pageSize query int false "Размер страницы (по умолчанию 10)"
 // @Success 200 {array} models.Song
+// @Header 200 {string} X-Did-You-Mean "Подсказка, если по названию песни или группы ничего не найдено"
+// @Failure 400 {object} models.ErrorResponse "Неизвестная сортировка"
 // @Failure 500 {object} models.ErrorResponse
 // @Router /songs [get]
 func GetSongs(c *gin.Context) {
 	logger.Log.Info("Получение списка песен")
 	var songs []models.Song
-	query := database.DB.Preload("Artist").Model(&models.Song{})
+	query := applySongFilters(database.DB.Scopes(preloadSongRelations).Model(&models.Song{}), c)
 
+	query, err := sortSongs(query, c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	offset := (page - 1) * pageSize
+	logger.Log.Debugf("Пагинация - страница: %d, размер: %d, offset: %d", page, pageSize, offset)
+
+	if err := query.Limit(pageSize).Offset(offset).Find(&songs).Error; err != nil {
+		logger.Log.Errorf("Ошибка при получении песен: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(songs) == 0 && page <= 1 {
+		if name := c.Query("song") + " " + c.Query("group"); strings.TrimSpace(name) != "" {
+			if suggestion, err := services.Suggestion(database.DB, strings.TrimSpace(name)); err != nil {
+				logger.Log.Errorf("Ошибка подбора подсказки: %v", err)
+			} else if suggestion != "" {
+				c.Header("X-Did-You-Mean", suggestion)
+			}
+		}
+	}
+	logger.Log.Info("Песни успешно получены")
+	c.JSON(http.StatusOK, songs)
+}
+
+// filterParams — источник параметров фильтра: query-параметры запроса
+// (*gin.Context) или аргументы запроса GraphQL.
+type filterParams interface {
+	Query(key string) string
+	QueryArray(key string) []string
+}
+
+var errUnknownSongSort = errors.New("Неизвестная сортировка, допустимо popularity, releaseDate, -releaseDate")
+
+// sortSongs применяет к списку песен сортировку из параметра sort.
+func sortSongs(query *gorm.DB, sort string) (*gorm.DB, error) {
+	switch sort {
+	case "":
+	case "popularity":
+		query = query.Select("songs.*").
+			Joins("LEFT JOIN song_stats ON song_stats.song_id = songs.id").
+			Order(services.PopularitySQL + " DESC").
+			Order("songs.id")
+	case "releaseDate":
+		// Даты хранятся строками ISO, поэтому строковый порядок хронологический,
+		// а «2006» идёт перед «2006-07-16».
+		query = query.Order("songs.release_date ASC NULLS LAST, songs.id")
+	case "-releaseDate":
+		query = query.Order("songs.release_date DESC NULLS LAST, songs.id")
+	default:
+		return query, errUnknownSongSort
+	}
+	return query, nil
+}
+
+// applySongFilters применяет к запросу фильтры из query-параметров, общие для списка и экспорта песен.
+func applySongFilters(query *gorm.DB, c filterParams) *gorm.DB {
 	if group := c.Query("group"); group != "" {
-		query = query.Joins("JOIN artists ON artists.id = songs.artist_id").
-			Where("artists.name ILIKE ?", "%"+group+"%")
+		artistIDs := artistsMatching(group)
+		if role := strings.ToLower(c.Query("role")); role != "" {
+			query = query.Where("EXISTS (SELECT 1 FROM song_credits WHERE song_credits.song_id = songs.id AND song_credits.role = ? AND song_credits.artist_id IN (?))", role, artistIDs)
+		} else {
+			query = query.Where("songs.artist_id IN (?) OR EXISTS (SELECT 1 FROM song_credits WHERE song_credits.song_id = songs.id AND song_credits.artist_id IN (?))", artistIDs, artistIDs)
+		}
 		logger.Log.Debugf("Фильтрация по группе: %s", group)
 	}
 
 	if songTitle := c.Query("song"); songTitle != "" {
-		query = query.Where("songs.song ILIKE ?", "%"+songTitle+"%")
+		query = query.Scopes(matchName("songs.song", songTitle))
 		logger.Log.Debugf("Фильтрация по названию песни: %s", songTitle)
 	}
 
+	// Некорректная дата, как и некорректный albumId, просто ничего не находит.
 	if releaseDate := c.Query("releaseDate"); releaseDate != "" {
-		query = query.Where("DATE(songs.release_date) = ?", releaseDate)
+		if period, err := models.ParsePartialDate(releaseDate); err == nil {
+			query = query.Where("songs.release_date LIKE ?", period.String()+"%")
+		} else {
+			query = query.Where("FALSE")
+		}
 		logger.Log.Debugf("Фильтрация по дате релиза: %s", releaseDate)
 	}
+	if from := c.Query("releasedFrom"); from != "" {
+		if period, err := models.ParsePartialDate(from); err == nil {
+			query = query.Where("songs.release_date >= ?", period.String())
+		} else {
+			query = query.Where("FALSE")
+		}
+	}
+	if to := c.Query("releasedTo"); to != "" {
+		if period, err := models.ParsePartialDate(to); err == nil {
+			query = query.Where("songs.release_date < ? OR songs.release_date LIKE ?", period.String(), period.String()+"%")
+		} else {
+			query = query.Where("FALSE")
+		}
+	}
 
 	if text := c.Query("text"); text != "" {
-		query = query.Where("songs.text ILIKE ?", "%"+text+"%")
+		query = query.Where("songs.text ILIKE ?", search.Contains(text))
 		logger.Log.Debugf("Фильтрация по тексту: %s", text)
 	}
 
 	if link := c.Query("link"); link != "" {
-		query = query.Where("songs.link = ?", link)
-		logger.Log.Debugf("Фильтрация по ссылке: %s", link)
+		canonical, err := services.CanonicalizeLink(link)
+		if err != nil {
+			canonical = link
+		}
+		query = query.Where("songs.link = ? OR EXISTS (SELECT 1 FROM song_links WHERE song_links.song_id = songs.id AND song_links.url = ?)", link, canonical)
+		logger.Log.Debugf("Фильтрация по ссылке: %s", canonical)
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	offset := (page - 1) * pageSize
-	logger.Log.Debugf("Пагинация - страница: %d, размер: %d, offset: %d", page, pageSize, offset)
+	if platforms := c.QueryArray("platform"); len(platforms) > 0 {
+		platforms = splitCommaList(platforms)
+		query = query.Where("EXISTS (SELECT 1 FROM song_links WHERE song_links.song_id = songs.id AND song_links.platform IN ?)", platforms)
+		logger.Log.Debugf("Фильтрация по платформам: %v", platforms)
+	}
 
-	if err := query.Limit(pageSize).Offset(offset).Find(&songs).Error; err != nil {
-		logger.Log.Errorf("Ошибка при получении песен: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
-		return
+	if albumID := c.Query("albumId"); albumID != "" {
+		// Некорректный ID просто ничего не находит, а не роняет запрос ошибкой БД.
+		id, _ := strconv.ParseUint(albumID, 10, 64)
+		query = query.Where("EXISTS (SELECT 1 FROM album_tracks WHERE album_tracks.song_id = songs.id AND album_tracks.album_id = ?)", id)
+		logger.Log.Debugf("Фильтрация по ID альбома: %s", albumID)
 	}
-	logger.Log.Info("Песни успешно получены")
-	c.JSON(http.StatusOK, songs)
+
+	if album := c.Query("album"); album != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM album_tracks JOIN albums ON albums.id = album_tracks.album_id WHERE album_tracks.song_id = songs.id AND albums.title ILIKE ?)", search.Contains(album))
+		logger.Log.Debugf("Фильтрация по альбому: %s", album)
+	}
+
+	if albumType := c.Query("albumType"); albumType != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM album_tracks JOIN albums ON albums.id = album_tracks.album_id WHERE album_tracks.song_id = songs.id AND albums.type = ?)", strings.ToLower(albumType))
+		logger.Log.Debugf("Фильтрация по типу альбома: %s", albumType)
+	}
+
+	if genres := splitCommaList(c.QueryArray("genre")); len(genres) > 0 {
+		for i := range genres {
+			genres[i] = strings.ToLower(genres[i])
+		}
+		const cond = "EXISTS (SELECT 1 FROM song_genres WHERE song_genres.song_id = songs.id AND song_genres.genre_id IN (" + services.GenreSubtreeSQL + "))"
+		if matchAll(c.Query("genreMode")) {
+			for _, genre := range genres {
+				query = query.Where(cond, []string{genre})
+			}
+		} else {
+			query = query.Where(cond, genres)
+		}
+		logger.Log.Debugf("Фильтрация по жанрам: %v", genres)
+	}
+
+	if tags := splitCommaList(c.QueryArray("tag")); len(tags) > 0 {
+		for i := range tags {
+			tags[i], _ = services.NormalizeTag(tags[i])
+		}
+		const cond = "EXISTS (SELECT 1 FROM song_tags JOIN tags ON tags.id = song_tags.tag_id WHERE song_tags.song_id = songs.id AND tags.name IN ?)"
+		if matchAll(c.Query("tagMode")) {
+			for _, tag := range tags {
+				query = query.Where(cond, []string{tag})
+			}
+		} else {
+			query = query.Where(cond, tags)
+		}
+		logger.Log.Debugf("Фильтрация по тегам: %v", tags)
+	}
+	return query
+}
+
+// artistsMatching — подзапрос ID артистов, чьё имя или псевдоним содержит group.
+func artistsMatching(group string) *gorm.DB {
+	names := database.DB.Model(&models.Artist{}).Select("id").Scopes(matchName("name", group))
+	aliases := database.DB.Model(&models.ArtistAlias{}).Select("artist_id").Scopes(matchName("name", group))
+	return database.DB.Model(&models.Artist{}).Select("id").Where("id IN (?) OR id IN (?)", names, aliases)
+}
+
+// matchName ищет подстроку в названии без учёта регистра, а также в ключе поиска
+// (без диакритики, кириллица в латинице), чтобы "kino" находило "Кино". Ключ
+// используется, только если запрос отличается от него диакритикой или письменностью:
+// запрос со знаками ("100%", "a_b", "AC/DC") ищется буквально.
+// Колонка ключа — search_key в той же таблице, что и column.
+func matchName(column, value string) func(*gorm.DB) *gorm.DB {
+	keyColumn := "search_key"
+	if table, _, ok := strings.Cut(column, "."); ok {
+		keyColumn = table + ".search_key"
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		if key := search.Key(value); key != "" && search.FoldsOnly(value) {
+			return db.Where(column+" ILIKE ? OR "+keyColumn+" LIKE ?", search.Contains(value), search.Contains(key))
+		}
+		return db.Where(column+" ILIKE ?", search.Contains(value))
+	}
+}
+
+// matchAll сообщает, нужно ли совпадение со всеми значениями фильтра (режим and),
+// а не хотя бы с одним (or, по умолчанию).
+func matchAll(mode string) bool {
+	return strings.EqualFold(mode, "and")
+}
+
+// preloadSongRelations подгружает связанные с песней данные для выдачи клиенту.
+func preloadSongRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Artist").
+		Preload("Links").
+		Preload("Credits", func(tx *gorm.DB) *gorm.DB { return tx.Order("song_credits.position, song_credits.id") }).
+		Preload("Credits.Artist").
+		Preload("Genres", func(tx *gorm.DB) *gorm.DB { return tx.Order("genres.name") }).
+		Preload("Tags", func(tx *gorm.DB) *gorm.DB { return tx.Order("tags.name") })
+}
+
+// splitCommaList разворачивает значения вида ?x=a,b&x=c в список [a b c].
+func splitCommaList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+// versePage возвращает страницу секций текста в виде строк, как поле verses
+// GET /songs/{id}/text, и общее число секций.
+func versePage(text string, page, pageSize int) ([]string, int) {
+	sections := services.ParseLyrics(text)
+	start := min((page-1)*pageSize, len(sections))
+	end := min(start+pageSize, len(sections))
+	verses := make([]string, 0, end-start)
+	for _, section := range sections[start:end] {
+		verses = append(verses, strings.Join(section.Lines, "\n"))
+	}
+	return verses, len(sections)
 }
 
 // GetSong godoc
 // @Summary Получение детальной информации о песне
-// @Description Возвращает информацию о песне по указанному ID, включая данные артиста.
+// @Description Возвращает информацию о песне по указанному ID, включая данные артиста. Текст возвращается на языке из параметра lang или заголовка Accept-Language, если есть такой перевод, иначе на языке оригинала; поле lang содержит язык текста.
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param id path int true "ID песни"
+// @Param lang query string false "Предпочтительный язык текста, например en (важнее Accept-Language)"
+// @Param Accept-Language header string false "Предпочтительные языки текста"
 // @Success 200 {object} models.Song "Данные песни, включая артиста"
 // @Failure 404 {object} models.ErrorResponse "Песня не найдена"
 // @Router /songs/{id} [get]
@@ -94,40 +309,45 @@ func GetSong(c *gin.Context) {
 
 	var song models.Song
 
-	cachedData, err := cache.Rdb.Get(context.Background(), "song:"+id).Result()
-	if err == nil {
+	if cachedData, ok := cache.Get("song:" + id); ok {
 		logger.Log.Infof("Песня есть в кеше id песни: %s", id)
-		if jsonErr := json.Unmarshal([]byte(cachedData), &song); jsonErr == nil {
+		if jsonErr := json.Unmarshal(cachedData, &song); jsonErr == nil {
+			applyTranslation(c, &song)
 			c.JSON(http.StatusOK, song)
 			return
 		}
 	}
 
-	if err := database.DB.Preload("Artist").First(&song, id).Error; err != nil {
+	if err := database.DB.Scopes(preloadSongRelations).First(&song, id).Error; err != nil {
 		logger.Log.Errorf("Песня не найдена: %v", err)
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
 		return
 	}
 
 	dataBytes, _ := json.Marshal(song)
-	cache.Rdb.Set(context.Background(), "song:"+id, dataBytes, 5*time.Minute)
+	cache.Set("song:"+id, dataBytes, 5*time.Minute)
 	logger.Log.Infof("Песня добавлена в кеш %s", dataBytes)
 
 	logger.Log.Infof("Песня успешно получена: %+v", song)
+	applyTranslation(c, &song)
 	c.JSON(http.StatusOK, song)
 }
 
 // GetSongText godoc
 // @Summary Получение текста песни с пагинацией по куплетам
-// @Description Разбивает текст песни на куплеты и возвращает запрошенную страницу.
+// @Description Разбирает текст песни (или синхронизированный текст, если он загружен) на секции (куплет, припев, бридж, вступление, концовка) по пустым строкам, меткам вида [Chorus] и повторяющимся блокам и возвращает запрошенную страницу. В поле verses те же секции в виде строк.
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param id path int true "ID песни"
+// @Param type query string false "Вернуть только секции указанного типа: verse, chorus, prechorus, bridge, intro, outro, other"
+// @Param lang query string false "Язык текста (важнее Accept-Language); если перевода нет, возвращается оригинал"
+// @Param align query string false "Язык второй колонки: в поле aligned секции страницы сопоставляются с секциями текста на этом языке"
+// @Param Accept-Language header string false "Предпочтительные языки текста"
 // @Param page query int false "Номер страницы (по умолчанию 1)"
 // @Param pageSize query int false "Размер страницы (по умолчанию 5)"
-// @Success 200 {object} models.MessageResponse "Ответ содержит массив строк куплетов"
-// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Success 200 {object} models.SongTextResponse "Секции текста и их строковое представление"
+// @Failure 404 {object} models.ErrorResponse "Песня или перевод для align не найдены"
 // @Router /songs/{id}/text [get]
 func GetSongText(c *gin.Context) {
 	id := c.Param("id")
@@ -140,26 +360,51 @@ func GetSongText(c *gin.Context) {
 		return
 	}
 
-	verses := services.SplitVerses(song.Text)
+	original := song
+	song.Text = songLyricsText(song)
+	applyTranslation(c, &song)
+
+	sections := services.FilterSections(services.ParseLyrics(song.Text), c.Query("type"))
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "5"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 5
+	}
 	start := (page - 1) * pageSize
 	end := start + pageSize
 
-	if start >= len(verses) {
-		c.JSON(http.StatusOK, gin.H{"verses": []string{}})
+	if start >= len(sections) {
+		c.JSON(http.StatusOK, models.SongTextResponse{Lang: song.Lang, Verses: []string{}, Sections: []models.LyricsSection{}})
 		return
 	}
-	if end > len(verses) {
-		end = len(verses)
+	if end > len(sections) {
+		end = len(sections)
+	}
+
+	resp := models.SongTextResponse{Lang: song.Lang, Sections: sections[start:end]}
+	for _, section := range resp.Sections {
+		resp.Verses = append(resp.Verses, strings.Join(section.Lines, "\n"))
+	}
+
+	if align := c.Query("align"); align != "" {
+		alignText, err := lyricsTextForLang(original, align)
+		if err != nil {
+			logger.Log.Errorf("Ошибка получения текста для выравнивания: %v", err)
+			c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+			return
+		}
+		resp.Aligned = services.AlignSections(resp.Sections, services.ParseLyrics(alignText))
 	}
 	logger.Log.Info("Успешно получен текст песни")
-	c.JSON(http.StatusOK, gin.H{"verses": verses[start:end]})
+	c.JSON(http.StatusOK, resp)
 }
 
 // DeleteSong godoc
 // @Summary Удаление песни
-// @Description Удаляет песню по указанному ID.
+// @Description Удаляет песню по указанному ID вместе с переводами, синхронизированным текстом, местами в альбомах и плейлистах, избранным и статистикой. Эти данные сохраняются в архив, и POST /songs/{id}/restore возвращает их вместе с песней.
 // @Tags songs
 // @Accept json
 // @Produce json
@@ -170,19 +415,22 @@ func GetSongText(c *gin.Context) {
 func DeleteSong(c *gin.Context) {
 	id := c.Param("id")
 	logger.Log.Infof("Удаление песни id: %s", id)
-	if err := database.DB.Delete(&models.Song{}, id).Error; err != nil {
-		logger.Log.Errorf("Песня не найдена: %v", err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Песня не найдена"})
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+	if err := deleteSong(songID); err != nil {
+		logger.Log.Errorf("Ошибка удаления песни: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
 		return
 	}
-	cache.Rdb.Del(context.Background(), "song:"+id)
 	logger.Log.Info("Песня успешно удалена в БД")
 	c.JSON(http.StatusOK, gin.H{"message": "Песня удалена"})
 }
 
 // PatchSong godoc
 // @Summary Частичное обновление данных песни
-// @Description Обновляет указанные поля песни по ID. Если поле не передано, оно не изменяется.
+// @Description Обновляет указанные поля песни по ID. Если поле не передано, оно не изменяется. Поле group переименовывает основного исполнителя песни: новое имя видно во всех его песнях, а имя или псевдоним другого артиста занять нельзя (409, артистов нужно объединить через merge). Поле moveTo переносит песню к артисту с таким именем (артист создаётся, если его нет); строка вида «A feat. B» заменяет основного и приглашённых исполнителей. Передавать group и moveTo вместе нельзя.
 // @Tags songs
 // @Accept json
 // @Produce json
@@ -190,18 +438,16 @@ func DeleteSong(c *gin.Context) {
 // @Param song body models.SongUpdate true "Данные для обновления песни (releaseDate в формате YYYY-MM-DD)"
 // @Success 200 {object} models.Song "Обновлённые данные песни"
 // @Failure 400 {object} models.ErrorResponse "Ошибка в запросе или данные невалидны"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Failure 409 {object} models.ErrorResponse "Новое имя в group занято другим артистом"
 // @Router /songs/{id} [patch]
 func PatchSong(c *gin.Context) {
 	id := c.Param("id")
 	logger.Log.Infof("Частичное обновление песни id: %s", id)
-
-	var song models.Song
-	if err := database.DB.First(&song, id).Error; err != nil {
-		logger.Log.Errorf("Песня не найдена: %v", err)
-		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+	songID, ok := parseSongID(c)
+	if !ok {
 		return
 	}
-	logger.Log.Debugf("Исходная песня: %+v", song)
 
 	var input models.SongUpdate
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -211,53 +457,26 @@ func PatchSong(c *gin.Context) {
 	}
 	logger.Log.Debugf("Полученные данные для обновления: %+v", input)
 
-	if input.GroupName != nil {
-		var artist models.Artist
-		if err := database.DB.First(&artist, song.ArtistID).Error; err != nil {
-			logger.Log.Errorf("Артист не найден: %v", err)
-			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Артист не найден"})
-			return
-		}
-		artist.Name = *input.GroupName
-		if err := database.DB.Save(&artist).Error; err != nil {
-			logger.Log.Errorf("Ошибка обновления артиста: %v", err)
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
-			return
-		}
-		song.Artist = artist
-	}
-	if input.Song != nil {
-		song.Song = *input.Song
-	}
-	if input.ReleaseDate != nil {
-		song.ReleaseDate = *input.ReleaseDate
-	}
-	if input.Text != nil {
-		song.Text = *input.Text
-	}
-	if input.Link != nil {
-		song.Link = *input.Link
-	}
-
-	if err := database.DB.Save(&song).Error; err != nil {
-		logger.Log.Errorf("Ошибка сохранения обновлений в БД: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	song, err := updateSong(songID, input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка обновления песни: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
 		return
 	}
-	cache.Rdb.Del(context.Background(), "song:"+id)
 	logger.Log.Info("Песня успешно обновлена в БД")
 	c.JSON(http.StatusOK, song)
 }
 
 // AddSong godoc
 // @Summary Добавление новой песни
-// @Description Добавляет новую песню, обогащая данные через внешний API. Если артист с указанным именем не существует, он создается.
+// @Description Добавляет новую песню, обогащая данные через внешний API. Если артист с указанным именем не существует, он создается. Строка группы вида «A feat. B, C» (также ft. и featuring) разбирается на основного и приглашённых исполнителей.
 // @Tags songs
 // @Accept json
 // @Produce json
-// @Param song body map[string]string true "Данные песни (обязательные поля: group и song. Чувствителен к регистру.)"
+// @Param song body map[string]string true "Данные песни (обязательные поля: group и song. Чувствителен к регистру.) Без обогащения также принимаются releaseDate, text и link"
+// @Param enrich query bool false "Обогащать данные через внешний API; с enrich=false песня сохраняется с переданными полями" default(true)
 // @Success 201 {object} models.Song "Созданная песня с данными из внешнего API"
-// @Failure 400 {object} models.ErrorResponse "Ошибка валидации входных данных"
+// @Failure 400 {object} models.ErrorResponse "Ошибка валидации входных данных, в том числе некорректная releaseDate с enrich=false"
 // @Failure 500 {object} models.ErrorResponse "Ошибка при получении данных с внешнего API или сохранении в БД"
 // @Router /songs [post]
 func AddSong(c *gin.Context) {
@@ -277,49 +496,139 @@ func AddSong(c *gin.Context) {
 		return
 	}
 
-	detail, err := services.FetchSongDetail(group, songTitle)
+	var detail *models.SongDetail
+	if c.Query("enrich") == "false" {
+		logger.Log.Debug("Песня добавляется без обращения к внешнему API")
+		// Дату от внешнего API CreateSong при ошибке пропускает, но переданную
+		// вручную молча терять нельзя.
+		if _, err := services.ParseReleaseDate(input["releaseDate"]); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		detail = &models.SongDetail{ReleaseDate: input["releaseDate"], Text: input["text"], Link: input["link"]}
+	} else {
+		var err error
+		detail, err = services.FetchSongDetail(group, songTitle)
+		if err != nil {
+			logger.Log.Errorf("Ошибка получения данных с внешнего API: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось получить информацию о песне"})
+			return
+		}
+		logger.Log.Debugf("Данные полученные о песне с внешнего API: %v", detail)
+	}
+
+	newSong, err := createSong(group, songTitle, detail)
 	if err != nil {
-		logger.Log.Errorf("Ошибка получения данных с внешнего API: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось получить информацию о песне"})
+		logger.Log.Errorf("Ошибка создания записи в БД о песне: %v", err)
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	logger.Log.Debugf("Данные полученные о песне с внешнего API: %v", detail)
+	logger.Log.Info("Песня успешно сохранена в БД")
+	c.JSON(http.StatusCreated, newSong)
+}
 
-	var artist models.Artist
-	if err := database.DB.Where("name ILIKE ?", group).First(&artist).Error; err != nil {
-		artist = models.Artist{
-			Name: group,
-		}
-		if err := database.DB.Create(&artist).Error; err != nil {
-			logger.Log.Errorf("Ошибка создания артиста: %v", err)
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
-			return
-		}
-		logger.Log.Infof("Создан новый артист: %v", artist)
-	} else {
-		logger.Log.Infof("Найден существующий артист: %v", artist)
+// createSong сохраняет новую песню и обновляет подсказки. Общая часть AddSong
+// и мутации GraphQL addSong.
+func createSong(group, title string, detail *models.SongDetail) (models.Song, error) {
+	var song models.Song
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		song, err = services.CreateSong(tx, group, title, detail)
+		return err
+	})
+	if err != nil {
+		return song, err
+	}
+	refreshSuggestions(song.ID)
+	return song, nil
+}
+
+// updateSong изменяет песню и сбрасывает её кеш. Общая часть PatchSong и
+// мутации GraphQL patchSong.
+func updateSong(songID uint, input models.SongUpdate) (models.Song, error) {
+	var song models.Song
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		song, err = services.UpdateSong(tx, songID, input)
+		return err
+	})
+	if err != nil {
+		return song, err
+	}
+	changed := []uint{songID}
+	if input.GroupName != nil {
+		changed = append(changed, renamedArtistSongs(song.ArtistID)...)
+	}
+	invalidateSongs(changed)
+	refreshSuggestions(changed...)
+	return song, nil
+}
+
+// renamedArtistSongs возвращает песни переименованного артиста: новое имя
+// попадает в их карточки, поэтому их кеш и подсказки нужно обновить.
+func renamedArtistSongs(artistID uint) []uint {
+	songIDs, err := services.ArtistSongIDs(database.DB, artistID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка поиска песен артиста %d: %v", artistID, err)
 	}
+	return songIDs
+}
 
-	layout := "02.01.2006"
-	parsedDate, err := time.Parse(layout, detail.ReleaseDate)
+// deleteSong удаляет песню и сбрасывает её кеш. Общая часть DeleteSong и
+// мутации GraphQL deleteSong.
+func deleteSong(songID uint) error {
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		return services.DeleteSong(tx, songID)
+	})
 	if err != nil {
-		logger.Log.Errorf("ошибка парсинга даты: %v", err)
+		return err
 	}
+	invalidateSongs([]uint{songID})
+	refreshSuggestions(songID)
+	return nil
+}
 
-	newSong := models.Song{
-		ArtistID:    artist.ID,
-		Song:        songTitle,
-		ReleaseDate: parsedDate,
-		Text:        detail.Text,
-		Link:        detail.Link,
+// parseSongID разбирает ID песни из пути. Если ID некорректен, отвечает 404.
+func parseSongID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		logger.Log.Errorf("Некорректный ID песни: %s", c.Param("id"))
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return 0, false
 	}
-	logger.Log.Debugf("Песня: %v", newSong)
+	return uint(id), true
+}
 
-	if err := database.DB.Create(&newSong).Error; err != nil {
-		logger.Log.Errorf("Ошибка создания записи в БД о песне: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// errorStatus сопоставляет ошибки сервисного слоя с HTTP-статусами.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrSongNotFound), errors.Is(err, services.ErrTranslationNotFound),
+		errors.Is(err, services.ErrAlbumNotFound), errors.Is(err, services.ErrGenreNotFound),
+		errors.Is(err, services.ErrPlaylistNotFound), errors.Is(err, services.ErrPlaylistItemNotFound),
+		errors.Is(err, services.ErrFavoriteNotFound), errors.Is(err, services.ErrArtistNotFound),
+		errors.Is(err, services.ErrArtistAliasNotFound), errors.Is(err, services.ErrWebhookNotFound),
+		errors.Is(err, services.ErrDeliveryNotFound), errors.Is(err, services.ErrLinkNotFound),
+		errors.Is(err, services.ErrDeletedSongNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidInput), errors.Is(err, services.ErrInvalidLang),
+		errors.Is(err, services.ErrInvalidAlbum), errors.Is(err, services.ErrInvalidAlbumType),
+		errors.Is(err, services.ErrInvalidCover), errors.Is(err, services.ErrInvalidTracks),
+		errors.Is(err, services.ErrInvalidCreditRole), errors.Is(err, services.ErrNoPrimaryCredit),
+		errors.Is(err, services.ErrInvalidGenre), errors.Is(err, services.ErrGenreCycle),
+		errors.Is(err, services.ErrInvalidGenres), errors.Is(err, services.ErrInvalidTag),
+		errors.Is(err, services.ErrInvalidPlaylist), errors.Is(err, services.ErrInvalidPlaylistItemIndex),
+		errors.Is(err, services.ErrInvalidPlayKind), errors.Is(err, services.ErrGroupWithMoveTo),
+		errors.Is(err, services.ErrEmptyQuery), errors.Is(err, services.ErrInvalidArtistAlias),
+		errors.Is(err, services.ErrInvalidArtistMerge), errors.Is(err, services.ErrInvalidWebhook),
+		errors.Is(err, services.ErrInvalidStatus), errors.Is(err, services.ErrInvalidEventType):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrOwnerRequired):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrPlaylistForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrGenreExists), errors.Is(err, services.ErrArtistAliasExists),
+		errors.Is(err, services.ErrSongExists):
+		return http.StatusConflict
 	}
-	logger.Log.Info("Песня успешно сохранена в БД")
-	c.JSON(http.StatusCreated, newSong)
+	return http.StatusInternalServerError
 }
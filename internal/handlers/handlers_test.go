@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"songs/internal/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestMatchName(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value    string
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{"100%", `SELECT * FROM "songs" WHERE songs.song ILIKE $1`, []interface{}{`%100\%%`}},
+		{"a_b", `SELECT * FROM "songs" WHERE songs.song ILIKE $1`, []interface{}{`%a\_b%`}},
+		{"AC/DC", `SELECT * FROM "songs" WHERE songs.song ILIKE $1`, []interface{}{`%AC/DC%`}},
+		{"Кино", `SELECT * FROM "songs" WHERE songs.song ILIKE $1 OR songs.search_key LIKE $2`, []interface{}{"%Кино%", "%kino%"}},
+		{"Beyoncé", `SELECT * FROM "songs" WHERE songs.song ILIKE $1 OR songs.search_key LIKE $2`, []interface{}{"%Beyoncé%", "%beyonce%"}},
+	}
+	for _, tt := range tests {
+		stmt := db.Scopes(matchName("songs.song", tt.value)).Find(&[]models.Song{}).Statement
+		if got := stmt.SQL.String(); got != tt.wantSQL {
+			t.Errorf("matchName(%q) SQL = %s, want %s", tt.value, got, tt.wantSQL)
+		}
+		if !reflect.DeepEqual(stmt.Vars, tt.wantVars) {
+			t.Errorf("matchName(%q) vars = %q, want %q", tt.value, stmt.Vars, tt.wantVars)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// internal/handlers/health_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"songs/internal/cache"
+	"songs/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthResponse описывает состояние сервиса и его зависимостей.
+type HealthResponse struct {
+	Status string       `json:"status" example:"ok"`
+	Cache  cache.Status `json:"cache"`
+}
+
+// GetHealth godoc
+// @Summary Проверка состояния сервиса
+// @Description Возвращает состояние сервиса. Если Redis недоступен, статус будет degraded, а кеш работает в памяти процесса.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponse
+// @Router /health [get]
+func GetHealth(c *gin.Context) {
+	status := cache.GetStatus()
+	resp := HealthResponse{Status: "ok", Cache: status}
+	if status.Degraded {
+		resp.Status = "degraded"
+		logger.Log.Warnf("Сервис работает в деградированном режиме: %s", status.LastError)
+	}
+	c.JSON(http.StatusOK, resp)
+}
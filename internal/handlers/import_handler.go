@@ -0,0 +1,85 @@
+// internal/handlers/import_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportSongs godoc
+// @Summary Массовый импорт песен из CSV или JSON Lines
+// @Description Принимает файл (поле file в multipart/form-data или тело запроса) с колонками group, song и необязательными releaseDate, text, link. Песни с уже существующей парой (группа, название) обновляются, остальные создаются. Импорт выполняется в фоне, прогресс доступен по id задачи.
+// @Tags songs
+// @Accept multipart/form-data,text/csv,application/x-ndjson
+// @Produce json
+// @Param file formData file false "Файл импорта"
+// @Param format query string false "Формат файла: csv или jsonl (по умолчанию определяется по имени файла или Content-Type)"
+// @Param dryRun query bool false "Только проверить строки, ничего не сохраняя; повтор песни в файле считается обновлением, как при настоящем импорте"
+// @Param enrich query bool false "Дополнять недостающие поля через внешний API"
+// @Success 202 {object} models.ImportJob "Созданная задача импорта"
+// @Failure 400 {object} models.ErrorResponse "Некорректный файл или формат"
+// @Router /songs/import [post]
+func ImportSongs(c *gin.Context) {
+	logger.Log.Info("Импорт песен")
+
+	body := c.Request.Body
+	filename := ""
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+		filename = header.Filename
+	}
+
+	format, err := services.DetectImportFormat(c.Query("format"), filename, c.ContentType())
+	if err != nil {
+		logger.Log.Errorf("Ошибка определения формата импорта: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	rows, err := services.ParseImport(body, format)
+	if err != nil {
+		logger.Log.Errorf("Ошибка чтения файла импорта: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Debugf("Прочитано строк для импорта: %d", len(rows))
+
+	opts := services.ImportOptions{
+		Format: format,
+		DryRun: c.Query("dryRun") == "true",
+		Enrich: c.Query("enrich") == "true",
+	}
+	job := services.StartImport(database.DB, rows, opts)
+	logger.Log.Infof("Создана задача импорта: %s", job.ID)
+
+	c.Header("Location", "/songs/import/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetImportJob godoc
+// @Summary Статус задачи импорта
+// @Description Возвращает прогресс импорта и ошибки валидации по строкам.
+// @Tags songs
+// @Produce json
+// @Param jobId path string true "ID задачи импорта"
+// @Success 200 {object} models.ImportJob
+// @Failure 404 {object} models.ErrorResponse "Задача не найдена"
+// @Router /songs/import/{jobId} [get]
+func GetImportJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	logger.Log.Infof("Получение статуса импорта: %s", jobID)
+
+	job, ok := services.GetImportJob(jobID)
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Задача импорта не найдена"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,154 @@
+// internal/handlers/link_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSongLinks godoc
+// @Summary Ссылки песни
+// @Description Возвращает все ссылки песни на площадки (YouTube, стриминги, магазины) с определённой платформой и типом.
+// @Tags links
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.SongLink
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/links [get]
+func GetSongLinks(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Получение ссылок песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.First(&models.Song{}, songID).Error; err != nil {
+		logger.Log.Errorf("Песня не найдена: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return
+	}
+
+	links := []models.SongLink{}
+	if err := database.DB.Where("song_id = ?", songID).Order("id").Find(&links).Error; err != nil {
+		logger.Log.Errorf("Ошибка получения ссылок: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, links)
+}
+
+// AddSongLink godoc
+// @Summary Добавление ссылки песни
+// @Description Канонизирует ссылку (https, без меток отслеживания, youtu.be -> youtube.com/watch), определяет платформу и сохраняет. Если такая ссылка уже есть, возвращается существующая. Тип определяется по платформе, если не передан.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param link body models.SongLinkInput true "Ссылка и необязательный тип: video, official_video, streaming, purchase, other"
+// @Success 201 {object} models.SongLink
+// @Failure 400 {object} models.ErrorResponse "Некорректная ссылка или тип"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/links [post]
+func AddSongLink(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Добавление ссылки песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var input models.SongLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON ссылки: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	link, err := services.NewSongLink(songID, input.URL, input.Kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := database.DB.First(&models.Song{}, songID).Error; err != nil {
+		logger.Log.Errorf("Песня не найдена: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return
+	}
+
+	err = database.WithTransaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "song_id"}, {Name: "url"}},
+			DoNothing: true,
+		}).Create(&link).Error
+		if err != nil {
+			return err
+		}
+		if link.ID == 0 {
+			// Такая ссылка уже есть: песня не изменилась, событие не нужно.
+			return tx.Where("song_id = ? AND url = ?", songID, link.URL).First(&link).Error
+		}
+		return services.PublishSongEvent(tx, services.EventSongUpdated, songID)
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка сохранения ссылки: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	cache.Del("song:" + id)
+	logger.Log.Infof("Ссылка сохранена: %s (%s)", link.URL, link.Platform)
+	c.JSON(http.StatusCreated, link)
+}
+
+// DeleteSongLink godoc
+// @Summary Удаление ссылки песни
+// @Tags links
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param linkId path int true "ID ссылки"
+// @Success 200 {object} models.MessageResponse "Ссылка удалена"
+// @Failure 404 {object} models.ErrorResponse "Ссылка не найдена"
+// @Router /songs/{id}/links/{linkId} [delete]
+func DeleteSongLink(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Удаление ссылки %s песни id: %s", c.Param("linkId"), id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	linkID, err := strconv.ParseUint(c.Param("linkId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Ссылка не найдена"})
+		return
+	}
+
+	err = database.WithTransaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND song_id = ?", linkID, songID).Delete(&models.SongLink{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return services.ErrLinkNotFound
+		}
+		return services.PublishSongEvent(tx, services.EventSongUpdated, songID)
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка удаления ссылки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	cache.Del("song:" + id)
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Ссылка удалена"})
+}
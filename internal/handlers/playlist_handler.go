@@ -0,0 +1,383 @@
+// internal/handlers/playlist_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Заголовок с идентификатором пользователя. Аутентификация выполняется шлюзом
+// перед сервисом, сюда приходит уже проверенный ID.
+const userIDHeader = "X-User-ID"
+
+// GetPlaylists godoc
+// @Summary Плейлисты пользователя
+// @Description Возвращает плейлисты текущего пользователя (без элементов), недавно изменённые первыми.
+// @Tags playlists
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Success 200 {array} models.Playlist
+// @Failure 401 {object} models.ErrorResponse "Не указан пользователь"
+// @Router /playlists [get]
+func GetPlaylists(c *gin.Context) {
+	userID := currentUserID(c)
+	logger.Log.Infof("Получение плейлистов пользователя %s", userID)
+
+	playlists, err := services.ListPlaylists(database.DB, userID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения плейлистов: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, playlists)
+}
+
+// AddPlaylist godoc
+// @Summary Создание плейлиста
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param playlist body models.PlaylistInput true "Данные плейлиста"
+// @Success 201 {object} models.Playlist
+// @Failure 400 {object} models.ErrorResponse "Пустое название"
+// @Failure 401 {object} models.ErrorResponse "Не указан пользователь"
+// @Router /playlists [post]
+func AddPlaylist(c *gin.Context) {
+	userID := currentUserID(c)
+	logger.Log.Infof("Создание плейлиста пользователем %s", userID)
+
+	var input models.PlaylistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON плейлиста: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	playlist, err := services.CreatePlaylist(database.DB, userID, input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка создания плейлиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// GetPlaylist godoc
+// @Summary Получение плейлиста
+// @Description Возвращает плейлист с песнями по порядку. Владелец видит все свои плейлисты, остальные пользователи — только публичные. Ссылка для шаринга показывается только владельцу.
+// @Tags playlists
+// @Produce json
+// @Param X-User-ID header string false "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} models.Playlist
+// @Failure 404 {object} models.ErrorResponse "Плейлист не найден"
+// @Router /playlists/{id} [get]
+func GetPlaylist(c *gin.Context) {
+	logger.Log.Infof("Получение плейлиста id: %s", c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+
+	playlist, err := services.FindPlaylist(database.DB, playlistID, currentUserID(c))
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения плейлиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, playlist)
+}
+
+// GetSharedPlaylist godoc
+// @Summary Плейлист по ссылке
+// @Description Возвращает плейлист по токену из ссылки для шаринга, в том числе приватный.
+// @Tags playlists
+// @Produce json
+// @Param token path string true "Токен ссылки"
+// @Success 200 {object} models.Playlist
+// @Failure 404 {object} models.ErrorResponse "Плейлист не найден или ссылка отозвана"
+// @Router /playlists/shared/{token} [get]
+func GetSharedPlaylist(c *gin.Context) {
+	logger.Log.Info("Получение плейлиста по ссылке")
+	playlist, err := services.FindSharedPlaylist(database.DB, c.Param("token"), currentUserID(c))
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения плейлиста по ссылке: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, playlist)
+}
+
+// PatchPlaylist godoc
+// @Summary Изменение плейлиста
+// @Description Меняет название, описание или видимость. Доступно только владельцу.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param playlist body models.PlaylistUpdate true "Поля для обновления"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} models.ErrorResponse "Пустое название"
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист не найден"
+// @Router /playlists/{id} [patch]
+func PatchPlaylist(c *gin.Context) {
+	logger.Log.Infof("Изменение плейлиста id: %s", c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PlaylistUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON плейлиста: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	playlist, err := services.UpdatePlaylist(database.DB, playlistID, currentUserID(c), input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка изменения плейлиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, playlist)
+}
+
+// DeletePlaylist godoc
+// @Summary Удаление плейлиста
+// @Tags playlists
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} models.MessageResponse "Плейлист удалён"
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист не найден"
+// @Router /playlists/{id} [delete]
+func DeletePlaylist(c *gin.Context) {
+	logger.Log.Infof("Удаление плейлиста id: %s", c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeletePlaylist(database.DB, playlistID, currentUserID(c)); err != nil {
+		logger.Log.Errorf("Ошибка удаления плейлиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Плейлист удалён"})
+}
+
+// SharePlaylist godoc
+// @Summary Ссылка для шаринга
+// @Description Выдаёт новую ссылку для шаринга плейлиста (GET /playlists/shared/{token}). Предыдущая ссылка перестаёт работать.
+// @Tags playlists
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} models.ShareTokenResponse
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист не найден"
+// @Router /playlists/{id}/share [post]
+func SharePlaylist(c *gin.Context) {
+	logger.Log.Infof("Создание ссылки для плейлиста id: %s", c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+
+	token, err := services.SharePlaylist(database.DB, playlistID, currentUserID(c))
+	if err != nil {
+		logger.Log.Errorf("Ошибка создания ссылки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.ShareTokenResponse{ShareToken: token})
+}
+
+// UnsharePlaylist godoc
+// @Summary Отзыв ссылки для шаринга
+// @Tags playlists
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} models.MessageResponse "Ссылка отозвана"
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист не найден"
+// @Router /playlists/{id}/share [delete]
+func UnsharePlaylist(c *gin.Context) {
+	logger.Log.Infof("Отзыв ссылки плейлиста id: %s", c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+
+	if err := services.UnsharePlaylist(database.DB, playlistID, currentUserID(c)); err != nil {
+		logger.Log.Errorf("Ошибка отзыва ссылки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Ссылка отозвана"})
+}
+
+// AddPlaylistItem godoc
+// @Summary Добавление песни в плейлист
+// @Description Вставляет песню по индексу (с нуля) или в конец, если индекс не передан. Остальные элементы не перенумеровываются.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param item body models.PlaylistItemInput true "Песня и индекс вставки"
+// @Success 201 {object} models.Playlist
+// @Failure 400 {object} models.ErrorResponse "Отрицательный индекс"
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист или песня не найдены"
+// @Router /playlists/{id}/items [post]
+func AddPlaylistItem(c *gin.Context) {
+	logger.Log.Infof("Добавление песни в плейлист id: %s", c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PlaylistItemInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON элемента плейлиста: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var playlist models.Playlist
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		playlist, err = services.AddPlaylistItem(tx, playlistID, currentUserID(c), input)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка добавления в плейлист: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// MovePlaylistItem godoc
+// @Summary Перемещение песни в плейлисте
+// @Description Переставляет элемент на указанный индекс (с нуля). Меняется позиция только перемещаемого элемента.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param itemId path int true "ID элемента плейлиста"
+// @Param move body models.PlaylistMoveInput true "Новый индекс"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} models.ErrorResponse "Отрицательный индекс"
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист или элемент не найдены"
+// @Router /playlists/{id}/items/{itemId} [patch]
+func MovePlaylistItem(c *gin.Context) {
+	logger.Log.Infof("Перемещение элемента %s плейлиста id: %s", c.Param("itemId"), c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+	itemID, ok := parsePlaylistItemID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PlaylistMoveInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON перемещения: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var playlist models.Playlist
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		playlist, err = services.MovePlaylistItem(tx, playlistID, itemID, currentUserID(c), input.Index)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка перемещения элемента плейлиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, playlist)
+}
+
+// RemovePlaylistItem godoc
+// @Summary Удаление песни из плейлиста
+// @Tags playlists
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID плейлиста"
+// @Param itemId path int true "ID элемента плейлиста"
+// @Success 200 {object} models.Playlist
+// @Failure 403 {object} models.ErrorResponse "Чужой плейлист"
+// @Failure 404 {object} models.ErrorResponse "Плейлист или элемент не найдены"
+// @Router /playlists/{id}/items/{itemId} [delete]
+func RemovePlaylistItem(c *gin.Context) {
+	logger.Log.Infof("Удаление элемента %s плейлиста id: %s", c.Param("itemId"), c.Param("id"))
+	playlistID, ok := parsePlaylistID(c)
+	if !ok {
+		return
+	}
+	itemID, ok := parsePlaylistItemID(c)
+	if !ok {
+		return
+	}
+
+	var playlist models.Playlist
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		playlist, err = services.RemovePlaylistItem(tx, playlistID, itemID, currentUserID(c))
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка удаления элемента плейлиста: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, playlist)
+}
+
+// currentUserID возвращает ID пользователя из заголовка X-User-ID.
+func currentUserID(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader(userIDHeader))
+}
+
+// parsePlaylistID разбирает ID плейлиста из пути. При некорректном значении отвечает 404.
+func parsePlaylistID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrPlaylistNotFound.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parsePlaylistItemID разбирает ID элемента плейлиста из пути.
+func parsePlaylistItemID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrPlaylistItemNotFound.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,89 @@
+// internal/handlers/routes.go
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes регистрирует маршруты API. Используется сервером и
+// songsctl, который в офлайн-режиме обрабатывает запросы в своём процессе.
+func RegisterRoutes(router gin.IRouter) {
+	router.GET("/suggest", Suggest)
+	router.GET("/songs", GetSongs)
+	router.GET("/songs/export", ExportSongs)
+	router.GET("/songs/facets", GetSongFacets)
+	router.GET("/songs/search", SearchSongs)
+	router.GET("/songs/popular", GetPopularSongs)
+	router.GET("/songs/trending", GetTrendingSongs)
+	router.GET("/songs/:id", GetSong)
+	router.GET("/songs/:id/text", GetSongText)
+	router.GET("/songs/:id/lyrics/synced", GetSyncedLyrics)
+	router.PUT("/songs/:id/lyrics/synced", PutSyncedLyrics)
+	router.DELETE("/songs/:id/lyrics/synced", DeleteSyncedLyrics)
+	router.GET("/songs/:id/links", GetSongLinks)
+	router.POST("/songs/:id/links", AddSongLink)
+	router.DELETE("/songs/:id/links/:linkId", DeleteSongLink)
+	router.GET("/songs/:id/credits", GetSongCredits)
+	router.PUT("/songs/:id/credits", PutSongCredits)
+	router.PUT("/songs/:id/genres", PutSongGenres)
+	router.PUT("/songs/:id/tags", PutSongTags)
+	router.POST("/songs/:id/plays", RecordPlay)
+	router.GET("/songs/:id/stats", GetSongStats)
+	router.PUT("/songs/:id/favorite", AddFavorite)
+	router.DELETE("/songs/:id/favorite", RemoveFavorite)
+	router.GET("/songs/:id/translations", GetSongTranslations)
+	router.PUT("/songs/:id/translations/:lang", PutSongTranslation)
+	router.DELETE("/songs/:id/translations/:lang", DeleteSongTranslation)
+	router.POST("/songs", AddSong)
+	router.POST("/songs/batch", BatchSongs)
+	router.POST("/songs/import", ImportSongs)
+	router.GET("/songs/import/:jobId", GetImportJob)
+	router.PATCH("/songs/:id", PatchSong)
+	router.DELETE("/songs/:id", DeleteSong)
+	router.POST("/songs/:id/restore", RestoreSong)
+	router.POST("/songs/:id/enrich", EnrichSong)
+
+	router.GET("/artists/:id", GetArtist)
+	router.POST("/artists/:id/aliases", AddArtistAlias)
+	router.DELETE("/artists/:id/aliases/:aliasId", DeleteArtistAlias)
+	router.POST("/artists/:id/merge", MergeArtist)
+
+	router.GET("/albums", GetAlbums)
+	router.POST("/albums", AddAlbum)
+	router.GET("/albums/:id", GetAlbum)
+	router.PATCH("/albums/:id", PatchAlbum)
+	router.PUT("/albums/:id/tracks", PutAlbumTracks)
+	router.DELETE("/albums/:id", DeleteAlbum)
+
+	router.GET("/genres", GetGenres)
+	router.POST("/genres", AddGenre)
+	router.PATCH("/genres/:id", PatchGenre)
+	router.DELETE("/genres/:id", DeleteGenre)
+	router.GET("/tags", GetTags)
+
+	router.GET("/favorites", GetFavorites)
+
+	router.GET("/changes", GetChanges)
+	router.GET("/events/stream", StreamEvents)
+	router.POST("/graphql", GraphQL)
+
+	router.GET("/webhooks", GetWebhooks)
+	router.POST("/webhooks", AddWebhook)
+	router.GET("/webhooks/:id", GetWebhook)
+	router.PATCH("/webhooks/:id", PatchWebhook)
+	router.DELETE("/webhooks/:id", DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", GetWebhookDeliveries)
+	router.POST("/webhooks/:id/deliveries/:deliveryId/retry", RetryWebhookDelivery)
+
+	router.GET("/playlists", GetPlaylists)
+	router.POST("/playlists", AddPlaylist)
+	router.GET("/playlists/shared/:token", GetSharedPlaylist)
+	router.GET("/playlists/:id", GetPlaylist)
+	router.PATCH("/playlists/:id", PatchPlaylist)
+	router.DELETE("/playlists/:id", DeletePlaylist)
+	router.POST("/playlists/:id/share", SharePlaylist)
+	router.DELETE("/playlists/:id/share", UnsharePlaylist)
+	router.POST("/playlists/:id/items", AddPlaylistItem)
+	router.PATCH("/playlists/:id/items/:itemId", MovePlaylistItem)
+	router.DELETE("/playlists/:id/items/:itemId", RemovePlaylistItem)
+
+	router.GET("/health", GetHealth)
+}
@@ -0,0 +1,51 @@
+// internal/handlers/search_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchSongs godoc
+// @Summary Нечёткий поиск песен
+// @Description Ищет песни по названию и имени артиста с учётом опечаток, диакритики и транслитерации («Kino» находит «Кино»). Результаты отсортированы по сходству. Если ничего не найдено, в didYouMean возвращается ближайшее известное название.
+// @Tags songs
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param limit query int false "Количество результатов" default(20)
+// @Success 200 {object} models.SongSearchResponse
+// @Failure 400 {object} models.ErrorResponse "Пустой поисковый запрос"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /songs/search [get]
+func SearchSongs(c *gin.Context) {
+	query := c.Query("q")
+	logger.Log.Infof("Поиск песен: %s", query)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSearchLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	response, err := services.SearchSongs(database.DB, query, limit)
+	if err != nil {
+		logger.Log.Errorf("Ошибка поиска песен: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
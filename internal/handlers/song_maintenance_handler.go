@@ -0,0 +1,95 @@
+// internal/handlers/song_maintenance_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RestoreSong godoc
+// @Summary Восстановление удалённой песни
+// @Description Восстанавливает удалённую песню с тем же ID по последнему событию song.deleted из ленты изменений: поля, ссылки, участников, жанры и теги. Артисты, которых уже нет (например, после объединения), находятся по имени или создаются заново. Переводы, синхронизированный текст, места в альбомах и плейлистах, избранное и статистика возвращаются из архива, сохранённого при удалении; альбомы и плейлисты, удалённые с тех пор, пропускаются, а занятая позиция в альбоме заменяется концом треклиста. Не возвращаются прослушивания, ещё не сброшенные в БД в момент удаления, удалённые жанры и теги, а у песен, удалённых до появления архива, — все связанные данные, кроме ссылок, участников, жанров и тегов.
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID удалённой песни"
+// @Success 201 {object} models.Song "Восстановленная песня"
+// @Failure 404 {object} models.ErrorResponse "Событие удаления песни не найдено"
+// @Failure 409 {object} models.ErrorResponse "Песня с таким ID существует"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /songs/{id}/restore [post]
+func RestoreSong(c *gin.Context) {
+	logger.Log.Infof("Восстановление песни id: %s", c.Param("id"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+	var song models.Song
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		song, err = services.RestoreSong(tx, songID)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка восстановления песни: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	invalidateSongs([]uint{songID})
+	refreshSuggestions(songID)
+	logger.Log.Info("Песня успешно восстановлена")
+	c.JSON(http.StatusCreated, song)
+}
+
+// EnrichSong godoc
+// @Summary Повторное обогащение песни
+// @Description Заново запрашивает данные песни во внешнем API по имени артиста и названию: дата релиза, текст и ссылка заменяются полученными (пустые значения не затирают текущие), дополнительные ссылки добавляются.
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.Song "Обновлённая песня"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Failure 500 {object} models.ErrorResponse "Ошибка внешнего API или БД"
+// @Router /songs/{id}/enrich [post]
+func EnrichSong(c *gin.Context) {
+	logger.Log.Infof("Повторное обогащение песни id: %s", c.Param("id"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+	var song models.Song
+	if err := database.DB.Preload("Artist").First(&song, songID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = services.ErrSongNotFound
+		}
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	detail, err := services.FetchSongDetail(song.Artist.Name, song.Song)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения данных с внешнего API: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Не удалось получить информацию о песне"})
+		return
+	}
+	err = database.WithTransaction(func(tx *gorm.DB) error {
+		song, err = services.EnrichSong(tx, songID, detail)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка обогащения песни: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	invalidateSongs([]uint{songID})
+	refreshSuggestions(songID)
+	logger.Log.Info("Песня успешно обогащена")
+	c.JSON(http.StatusOK, song)
+}
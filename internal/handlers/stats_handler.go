@@ -0,0 +1,210 @@
+// internal/handlers/stats_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRankingLimit = 20
+	maxRankingLimit     = 100
+)
+
+// RecordPlay godoc
+// @Summary Учёт прослушивания
+// @Description Учитывает прослушивание (listen) или просмотр клипа (view) песни. Счётчики копятся в Redis и периодически сбрасываются в БД, поэтому в статистике появляются с задержкой.
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param play body models.PlayInput false "Тип события: listen (по умолчанию) или view"
+// @Success 202 {object} models.MessageResponse "Событие принято"
+// @Failure 400 {object} models.ErrorResponse "Некорректный тип события"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/plays [post]
+func RecordPlay(c *gin.Context) {
+	logger.Log.Debugf("Прослушивание песни id: %s", c.Param("id"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var input models.PlayInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			logger.Log.Errorf("Ошибка при биндинге JSON события: %v", err)
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	if err := services.RecordPlay(database.DB, songID, input.Kind); err != nil {
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, models.MessageResponse{Message: "Событие принято"})
+}
+
+// GetSongStats godoc
+// @Summary Статистика песни
+// @Description Возвращает число прослушиваний, просмотров и добавлений в избранное. События за последние секунды могут быть ещё не учтены.
+// @Tags stats
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.SongStats
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/stats [get]
+func GetSongStats(c *gin.Context) {
+	logger.Log.Infof("Получение статистики песни id: %s", c.Param("id"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := services.FindSongStats(database.DB, songID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения статистики: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetPopularSongs godoc
+// @Summary Популярные песни
+// @Description Песни с наибольшей популярностью за всё время: прослушивания, просмотры (с весом 0.25) и добавления в избранное (с весом 3).
+// @Tags stats
+// @Produce json
+// @Param limit query int false "Количество песен" default(20)
+// @Success 200 {array} models.SongRanking
+// @Failure 500 {object} models.ErrorResponse
+// @Router /songs/popular [get]
+func GetPopularSongs(c *gin.Context) {
+	logger.Log.Info("Получение популярных песен")
+	respondRanking(c, services.PopularSongs)
+}
+
+// GetTrendingSongs godoc
+// @Summary Песни в тренде
+// @Description Песни, популярные в последнее время. Вклад прослушиваний и добавлений в избранное уменьшается вдвое каждые 72 часа.
+// @Tags stats
+// @Produce json
+// @Param limit query int false "Количество песен" default(20)
+// @Success 200 {array} models.SongRanking
+// @Failure 500 {object} models.ErrorResponse
+// @Router /songs/trending [get]
+func GetTrendingSongs(c *gin.Context) {
+	logger.Log.Info("Получение песен в тренде")
+	respondRanking(c, services.TrendingSongs)
+}
+
+// GetFavorites godoc
+// @Summary Избранное пользователя
+// @Tags favorites
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Success 200 {array} models.Favorite
+// @Failure 401 {object} models.ErrorResponse "Не указан пользователь"
+// @Router /favorites [get]
+func GetFavorites(c *gin.Context) {
+	userID := currentUserID(c)
+	logger.Log.Infof("Получение избранного пользователя %s", userID)
+
+	favorites, err := services.ListFavorites(database.DB, userID)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения избранного: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, favorites)
+}
+
+// AddFavorite godoc
+// @Summary Добавление в избранное
+// @Description Добавляет песню в избранное пользователя. Повторный запрос ничего не меняет.
+// @Tags favorites
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.MessageResponse "Песня в избранном"
+// @Failure 401 {object} models.ErrorResponse "Не указан пользователь"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/favorite [put]
+func AddFavorite(c *gin.Context) {
+	logger.Log.Infof("Добавление песни id: %s в избранное", c.Param("id"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var added bool
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		added, err = services.AddFavorite(tx, currentUserID(c), songID)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка добавления в избранное: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if added {
+		services.RecordFavoriteTrend(songID)
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Песня в избранном"})
+}
+
+// RemoveFavorite godoc
+// @Summary Удаление из избранного
+// @Tags favorites
+// @Produce json
+// @Param X-User-ID header string true "ID пользователя"
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.MessageResponse "Песня удалена из избранного"
+// @Failure 401 {object} models.ErrorResponse "Не указан пользователь"
+// @Failure 404 {object} models.ErrorResponse "Песни нет в избранном"
+// @Router /songs/{id}/favorite [delete]
+func RemoveFavorite(c *gin.Context) {
+	logger.Log.Infof("Удаление песни id: %s из избранного", c.Param("id"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		return services.RemoveFavorite(tx, currentUserID(c), songID)
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка удаления из избранного: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Песня удалена из избранного"})
+}
+
+func respondRanking(c *gin.Context, rank func(db *gorm.DB, limit int) ([]models.SongRanking, error)) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRankingLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultRankingLimit
+	}
+	if limit > maxRankingLimit {
+		limit = maxRankingLimit
+	}
+
+	rankings, err := rank(database.DB, limit)
+	if err != nil {
+		logger.Log.Errorf("Ошибка построения рейтинга: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rankings)
+}
@@ -0,0 +1,133 @@
+// internal/handlers/stream_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Комментарий-пинг не даёт прокси закрыть простаивающее соединение.
+	streamHeartbeatInterval = 15 * time.Second
+	// Через сколько миллисекунд браузер переподключается после обрыва.
+	streamRetryMillis = 3000
+)
+
+// StreamEvents godoc
+// @Summary Поток событий каталога (SSE)
+// @Description Держит соединение Server-Sent Events и присылает события каталога (song.created, song.updated, song.deleted, artist.*) по мере их фиксации. Поле id сообщения — номер события (seq), поле event — его тип, data — событие целиком в том же виде, что в GET /changes. После обрыва браузер сам переподключается с заголовком Last-Event-ID, и пропущенные события досылаются из ленты изменений. Поток работает на любом экземпляре сервиса: события расходятся между экземплярами через Redis.
+// @Tags changes
+// @Produce text/event-stream
+// @Param artistId query int false "Только события артиста: его песни (включая участие) и изменения самого артиста"
+// @Param type query string false "Типы событий через запятую, допускаются маски вида song.*"
+// @Param Last-Event-ID header int false "Номер последнего полученного события: дослать пропущенные"
+// @Param lastEventId query int false "То же, что Last-Event-ID, для клиентов без поддержки заголовка"
+// @Success 200 {object} models.Event "Поток событий text/event-stream"
+// @Failure 400 {object} models.ErrorResponse "Некорректный фильтр или Last-Event-ID"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /events/stream [get]
+func StreamEvents(c *gin.Context) {
+	var artistID uint64
+	if value := c.Query("artistId"); value != "" {
+		var err error
+		if artistID, err = strconv.ParseUint(value, 10, 64); err != nil || artistID == 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный ID артиста"})
+			return
+		}
+	}
+	filter, err := services.NewEventFilter(splitCommaList(c.QueryArray("type")), uint(artistID))
+	if err != nil {
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	var lastSeq uint64
+	resume := lastEventID != ""
+	if resume {
+		if lastSeq, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Некорректный Last-Event-ID, ожидается номер события"})
+			return
+		}
+	}
+	logger.Log.Infof("Подключение к потоку событий: artistId=%d, type=%v, Last-Event-ID=%q", artistID, filter.Types, lastEventID)
+
+	// Подписываемся до досылки пропущенного, чтобы не потерять события,
+	// зафиксированные во время чтения ленты; повторы отсекаются по seq.
+	events, cancel := services.SubscribeEvents()
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteString("retry: " + strconv.Itoa(streamRetryMillis) + "\n\n")
+	c.Writer.Flush()
+
+	send := func(event models.Event) bool {
+		if event.Seq <= lastSeq {
+			return true
+		}
+		lastSeq = event.Seq
+		if !filter.Match(event) {
+			return true
+		}
+		c.Render(-1, sse.Event{Id: strconv.FormatUint(event.Seq, 10), Event: event.Type, Data: event})
+		c.Writer.Flush()
+		return c.Request.Context().Err() == nil
+	}
+
+	if resume {
+		for {
+			changes, err := services.ListChanges(database.DB.WithContext(c.Request.Context()), lastSeq, services.MaxChangesLimit)
+			if err != nil {
+				logger.Log.Errorf("Ошибка досылки событий в поток: %v", err)
+				return
+			}
+			for _, event := range changes.Events {
+				if !send(event) {
+					return
+				}
+			}
+			// Номер сдвигаем и по отфильтрованным событиям: их не нужно читать повторно.
+			lastSeq = changes.NextSince
+			if !changes.HasMore {
+				break
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			logger.Log.Debug("Клиент отключился от потока событий")
+			return
+		case event, ok := <-events:
+			if !ok {
+				// Клиент не успевал читать: он переподключится с Last-Event-ID.
+				return
+			}
+			if !send(event) {
+				return
+			}
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
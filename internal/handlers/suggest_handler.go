@@ -0,0 +1,34 @@
+// internal/handlers/suggest_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Suggest godoc
+// @Summary Автодополнение для строки поиска
+// @Description Возвращает до 10 артистов и песен, название которых или одно из слов в нём начинается с запроса. Регистр, диакритика и раскладка кириллица/латиница не важны. Сначала точные совпадения и совпадения с начала названия, затем популярные. Ответ строится по индексу в памяти без обращения к БД, поэтому только что добавленные в обход API записи могут появиться с задержкой до 5 минут.
+// @Tags search
+// @Produce json
+// @Param q query string true "Начало названия"
+// @Success 200 {array} models.Suggestion
+// @Router /suggest [get]
+func Suggest(c *gin.Context) {
+	query := c.Query("q")
+	logger.Log.Debugf("Автодополнение: %s", query)
+	c.JSON(http.StatusOK, services.Suggest(query))
+}
+
+// refreshSuggestions обновляет индекс автодополнения после изменения песен.
+// Ошибка не мешает ответу: индекс догонит БД при следующей пересборке.
+func refreshSuggestions(songIDs ...uint) {
+	if err := services.UpdateSongSuggestions(database.DB, songIDs...); err != nil {
+		logger.Log.Errorf("Ошибка обновления индекса автодополнения: %v", err)
+	}
+}
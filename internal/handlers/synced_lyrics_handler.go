@@ -0,0 +1,178 @@
+// internal/handlers/synced_lyrics_handler.go
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Максимальный размер загружаемого LRC-файла.
+const maxLRCSize = 1 << 20
+
+// PutSyncedLyrics godoc
+// @Summary Загрузка синхронизированного текста (LRC)
+// @Description Принимает текст в формате LRC или расширенном LRC с пословными метками <mm:ss.xx> в теле запроса, проверяет метки времени и сохраняет их. Существующий синхронизированный текст заменяется.
+// @Tags lyrics
+// @Accept plain
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lrc body string true "Текст в формате LRC"
+// @Success 200 {object} models.SyncedLyrics "Сохранённые строки с метками времени"
+// @Failure 400 {object} models.ErrorResponse "Некорректный LRC"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/lyrics/synced [put]
+func PutSyncedLyrics(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Загрузка синхронизированного текста песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxLRCSize))
+	if err != nil {
+		logger.Log.Errorf("Ошибка чтения LRC: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Не удалось прочитать LRC: " + err.Error()})
+		return
+	}
+
+	lines, enhanced, err := services.ParseLRC(string(body))
+	if err != nil {
+		logger.Log.Errorf("Некорректный LRC: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Debugf("Разобрано строк LRC: %d, пословная разметка: %t", len(lines), enhanced)
+
+	if err := database.DB.First(&models.Song{}, songID).Error; err != nil {
+		logger.Log.Errorf("Песня не найдена: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return
+	}
+
+	synced := models.SyncedLyrics{SongID: songID, Enhanced: enhanced, Lines: lines}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "song_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enhanced", "lines", "updated_at"}),
+	}).Create(&synced).Error
+	if err != nil {
+		logger.Log.Errorf("Ошибка сохранения синхронизированного текста: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Info("Синхронизированный текст сохранён")
+	c.JSON(http.StatusOK, synced)
+}
+
+// GetSyncedLyrics godoc
+// @Summary Получение синхронизированного текста
+// @Description Возвращает строки с метками времени в JSON или LRC. С параметром at вместо всего текста возвращает строку, которая звучит в указанный момент (models.SyncedLineAt).
+// @Tags lyrics
+// @Produce json,plain
+// @Param id path int true "ID песни"
+// @Param format query string false "Формат ответа: json (по умолчанию) или lrc"
+// @Param at query number false "Момент воспроизведения в секундах, например 12.5"
+// @Success 200 {object} models.SyncedLyrics "Синхронизированный текст"
+// @Failure 400 {object} models.ErrorResponse "Некорректные параметры"
+// @Failure 404 {object} models.ErrorResponse "Синхронизированный текст не найден"
+// @Router /songs/{id}/lyrics/synced [get]
+func GetSyncedLyrics(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Получение синхронизированного текста песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	synced, err := findSyncedLyrics(songID)
+	if err != nil {
+		logger.Log.Errorf("Синхронизированный текст не найден: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Синхронизированный текст не найден"})
+		return
+	}
+
+	if at := c.Query("at"); at != "" {
+		seconds, err := strconv.ParseFloat(at, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Параметр at должен быть неотрицательным числом секунд"})
+			return
+		}
+		line, ok := services.SyncedLineAt(synced.Lines, int64(seconds*1000))
+		if !ok {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "В этот момент текст ещё не звучит"})
+			return
+		}
+		c.JSON(http.StatusOK, line)
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, synced)
+	case "lrc":
+		c.Header("Content-Disposition", "inline; filename=song-"+id+".lrc")
+		c.String(http.StatusOK, services.FormatLRC(synced.Lines))
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Неизвестный формат, допустимы json и lrc"})
+	}
+}
+
+// DeleteSyncedLyrics godoc
+// @Summary Удаление синхронизированного текста
+// @Tags lyrics
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.MessageResponse "Синхронизированный текст удалён"
+// @Failure 404 {object} models.ErrorResponse "Синхронизированный текст не найден"
+// @Router /songs/{id}/lyrics/synced [delete]
+func DeleteSyncedLyrics(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Удаление синхронизированного текста песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	result := database.DB.Where("song_id = ?", songID).Delete(&models.SyncedLyrics{})
+	if result.Error != nil {
+		logger.Log.Errorf("Ошибка удаления синхронизированного текста: %v", result.Error)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Синхронизированный текст не найден"})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Синхронизированный текст удалён"})
+}
+
+// findSyncedLyrics возвращает синхронизированный текст песни или gorm.ErrRecordNotFound.
+func findSyncedLyrics(songID uint) (models.SyncedLyrics, error) {
+	var synced models.SyncedLyrics
+	err := database.DB.Where("song_id = ?", songID).First(&synced).Error
+	return synced, err
+}
+
+// songLyricsText возвращает текст песни для разбора на куплеты: если у песни есть
+// синхронизированный текст, куплеты строятся по нему.
+func songLyricsText(song models.Song) string {
+	synced, err := findSyncedLyrics(song.ID)
+	if err == nil {
+		return services.SyncedToText(synced.Lines)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Log.Errorf("Ошибка получения синхронизированного текста: %v", err)
+	}
+	return song.Text
+}
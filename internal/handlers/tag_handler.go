@@ -0,0 +1,76 @@
+// internal/handlers/tag_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	"songs/database"
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetTags godoc
+// @Summary Список тегов
+// @Description Возвращает все теги с количеством песен, самые популярные первыми.
+// @Tags tags
+// @Produce json
+// @Success 200 {array} models.FacetCount
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tags [get]
+func GetTags(c *gin.Context) {
+	logger.Log.Info("Получение списка тегов")
+	tags, err := services.ListTags(database.DB)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения тегов: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// PutSongTags godoc
+// @Summary Замена тегов песни
+// @Description Полностью заменяет теги песни. Теги приводятся к нижнему регистру, новые создаются автоматически. Пустой список снимает все теги.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param tags body models.SongTagsInput true "Теги"
+// @Success 200 {array} models.Tag
+// @Failure 400 {object} models.ErrorResponse "Пустой или слишком длинный тег"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/tags [put]
+func PutSongTags(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Замена тегов песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var input models.SongTagsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON тегов: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var tags []models.Tag
+	err := database.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		tags, err = services.SetSongTags(tx, songID, input.Tags)
+		return err
+	})
+	if err != nil {
+		logger.Log.Errorf("Ошибка сохранения тегов: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	cache.Del("song:" + id)
+	c.JSON(http.StatusOK, tags)
+}
@@ -0,0 +1,249 @@
+// internal/handlers/translation_handler.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSongTranslations godoc
+// @Summary Список переводов песни
+// @Description Возвращает оригинальный текст (original=true, язык задаётся полем lang песни) и все переводы.
+// @Tags translations
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.SongTranslation
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/translations [get]
+func GetSongTranslations(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Получение переводов песни id: %s", id)
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	var song models.Song
+	if err := database.DB.First(&song, songID).Error; err != nil {
+		logger.Log.Errorf("Песня не найдена: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return
+	}
+
+	var translations []models.SongTranslation
+	if err := database.DB.Where("song_id = ?", songID).Order("lang").Find(&translations).Error; err != nil {
+		logger.Log.Errorf("Ошибка получения переводов: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	original := models.SongTranslation{
+		SongID:    song.ID,
+		Lang:      song.Lang,
+		Original:  true,
+		Text:      song.Text,
+		CreatedAt: song.CreatedAt,
+		UpdatedAt: song.UpdatedAt,
+	}
+	c.JSON(http.StatusOK, append([]models.SongTranslation{original}, translations...))
+}
+
+// PutSongTranslation godoc
+// @Summary Добавление или замена перевода
+// @Description Сохраняет перевод текста песни на указанный язык. Оригинальный текст меняется через PATCH /songs/{id}.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang path string true "Код языка, например en или pt-BR"
+// @Param translation body models.TranslationInput true "Текст перевода"
+// @Success 200 {object} models.SongTranslation
+// @Failure 400 {object} models.ErrorResponse "Некорректный код языка или текст"
+// @Failure 404 {object} models.ErrorResponse "Песня не найдена"
+// @Router /songs/{id}/translations/{lang} [put]
+func PutSongTranslation(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Сохранение перевода песни id: %s, язык: %s", id, c.Param("lang"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	lang, err := services.NormalizeLang(c.Param("lang"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var input models.TranslationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON перевода: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var song models.Song
+	if err := database.DB.First(&song, songID).Error; err != nil {
+		logger.Log.Errorf("Песня не найдена: %v", err)
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Песня не найдена"})
+		return
+	}
+	if song.Lang == lang {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Это язык оригинала, измените текст песни через PATCH /songs/{id}"})
+		return
+	}
+
+	translation := models.SongTranslation{SongID: songID, Lang: lang, Text: input.Text}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "song_id"}, {Name: "lang"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "updated_at"}),
+	}).Create(&translation).Error
+	if err != nil {
+		logger.Log.Errorf("Ошибка сохранения перевода: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Info("Перевод сохранён")
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteSongTranslation godoc
+// @Summary Удаление перевода
+// @Tags translations
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang path string true "Код языка"
+// @Success 200 {object} models.MessageResponse "Перевод удалён"
+// @Failure 404 {object} models.ErrorResponse "Перевод не найден"
+// @Router /songs/{id}/translations/{lang} [delete]
+func DeleteSongTranslation(c *gin.Context) {
+	id := c.Param("id")
+	logger.Log.Infof("Удаление перевода песни id: %s, язык: %s", id, c.Param("lang"))
+	songID, ok := parseSongID(c)
+	if !ok {
+		return
+	}
+
+	lang, err := services.NormalizeLang(c.Param("lang"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result := database.DB.Where("song_id = ? AND lang = ?", songID, lang).Delete(&models.SongTranslation{})
+	if result.Error != nil {
+		logger.Log.Errorf("Ошибка удаления перевода: %v", result.Error)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrTranslationNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Перевод удалён"})
+}
+
+// applyTranslation подменяет текст песни переводом, выбранным по параметру lang
+// или заголовку Accept-Language. Если перевода нет, остаётся оригинал.
+func applyTranslation(c *gin.Context, song *models.Song) {
+	c.Header("Vary", "Accept-Language")
+	prefs := services.PreferredLangs(c.Query("lang"), c.GetHeader("Accept-Language"))
+
+	translation, ok, err := services.FindTranslation(database.DB, song.ID, song.Lang, prefs)
+	if err != nil {
+		logger.Log.Errorf("Ошибка выбора перевода: %v", err)
+	}
+	if ok {
+		logger.Log.Debugf("Выбран перевод песни id: %d, язык: %s", song.ID, translation.Lang)
+		song.Text = translation.Text
+		song.Lang = translation.Lang
+	}
+	if song.Lang != "" {
+		c.Header("Content-Language", song.Lang)
+	}
+}
+
+// songLyricsSources — синхронизированный текст и переводы песни, из которых
+// строится текст для GET /songs/{id}/text, куплетов gRPC и GraphQL.
+type songLyricsSources struct {
+	synced       *models.SyncedLyrics
+	translations []models.SongTranslation
+}
+
+// loadSongLyrics загружает синхронизированные тексты и переводы песен двумя запросами.
+func loadSongLyrics(ctx context.Context, songIDs []uint) (map[uint]songLyricsSources, error) {
+	db := database.DB.WithContext(ctx)
+	var synced []models.SyncedLyrics
+	if err := db.Where("song_id IN ?", songIDs).Find(&synced).Error; err != nil {
+		return nil, err
+	}
+	var translations []models.SongTranslation
+	if err := db.Where("song_id IN ?", songIDs).Order("lang").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uint]songLyricsSources, len(songIDs))
+	for i := range synced {
+		sources := result[synced[i].SongID]
+		sources.synced = &synced[i]
+		result[synced[i].SongID] = sources
+	}
+	for _, translation := range translations {
+		sources := result[translation.SongID]
+		sources.translations = append(sources.translations, translation)
+		result[translation.SongID] = sources
+	}
+	return result, nil
+}
+
+// localizedLyrics выбирает текст песни так же, как GET /songs/{id}/text: перевод
+// на подходящем из prefs языке, иначе синхронизированный текст, если он загружен,
+// иначе текст песни. Возвращает текст и его язык.
+func localizedLyrics(song models.Song, sources songLyricsSources, prefs []string) (text, lang string) {
+	if len(prefs) > 0 && len(sources.translations) > 0 {
+		available := make([]string, len(sources.translations))
+		for i, translation := range sources.translations {
+			available[i] = translation.Lang
+		}
+		if selected := services.SelectLang(prefs, song.Lang, available); selected != "" && selected != song.Lang {
+			for _, translation := range sources.translations {
+				if translation.Lang == selected {
+					return translation.Text, translation.Lang
+				}
+			}
+		}
+	}
+	if sources.synced != nil {
+		return services.SyncedToText(sources.synced.Lines), song.Lang
+	}
+	return song.Text, song.Lang
+}
+
+// lyricsTextForLang возвращает текст песни на указанном языке: оригинал, если язык
+// совпадает с языком песни, иначе перевод.
+func lyricsTextForLang(song models.Song, lang string) (string, error) {
+	lang, err := services.NormalizeLang(lang)
+	if err != nil {
+		return "", err
+	}
+	if song.Lang != "" && services.LangMatches(song.Lang, lang) {
+		return songLyricsText(song), nil
+	}
+	translation, ok, err := services.FindTranslation(database.DB, song.ID, song.Lang, []string{lang})
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if !ok {
+		return "", services.ErrTranslationNotFound
+	}
+	return translation.Text, nil
+}
@@ -0,0 +1,217 @@
+// internal/handlers/webhook_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"songs/database"
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWebhooks godoc
+// @Summary Список подписок на вебхуки
+// @Description Возвращает подписки без секретов.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.WebhookSubscription
+// @Failure 500 {object} models.ErrorResponse
+// @Router /webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	logger.Log.Info("Получение списка подписок на вебхуки")
+	subscriptions, err := services.ListWebhooks(database.DB)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения подписок: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// GetWebhook godoc
+// @Summary Получение подписки на вебхуки
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 404 {object} models.ErrorResponse "Подписка не найдена"
+// @Router /webhooks/{id} [get]
+func GetWebhook(c *gin.Context) {
+	subscriptionID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	subscription, err := services.FindWebhook(database.DB, subscriptionID)
+	if err != nil {
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// AddWebhook godoc
+// @Summary Создание подписки на вебхуки
+// @Description Подписывает URL на события каталога: song.created, song.updated, song.deleted, artist.created, artist.updated, artist.merged. Допустимы маски song.* и artist.*, пустой список — все события. События отправляются POST-запросом с JSON вида {id, type, occurredAt, data}; заголовок X-Webhook-Signature содержит sha256=hex(HMAC-SHA256(secret, X-Webhook-Timestamp + "." + тело)). Неудачные доставки повторяются с растущей задержкой, после 10 попыток попадают в журнал недоставленных (status=dead). URL должен вести на публичный адрес: localhost, частные, link-local и другие внутренние адреса отклоняются, в том числе после разрешения имени при отправке. Секрет возвращается только в этом ответе.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body models.WebhookInput true "URL, типы событий и секрет"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} models.ErrorResponse "Некорректный URL или тип события"
+// @Router /webhooks [post]
+func AddWebhook(c *gin.Context) {
+	var input models.WebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON подписки: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Infof("Создание подписки на вебхуки: %s", input.URL)
+
+	subscription, err := services.CreateWebhook(database.DB, input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка создания подписки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// PatchWebhook godoc
+// @Summary Обновление подписки на вебхуки
+// @Description Меняет URL, типы событий, секрет или включает/отключает подписку. Пока подписка отключена, события копятся и отправляются после включения.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param webhook body models.WebhookUpdate true "Поля для обновления"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} models.ErrorResponse "Некорректные данные"
+// @Failure 404 {object} models.ErrorResponse "Подписка не найдена"
+// @Router /webhooks/{id} [patch]
+func PatchWebhook(c *gin.Context) {
+	logger.Log.Infof("Обновление подписки на вебхуки id: %s", c.Param("id"))
+	subscriptionID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	var input models.WebhookUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Log.Errorf("Ошибка при биндинге JSON подписки: %v", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	subscription, err := services.UpdateWebhook(database.DB, subscriptionID, input)
+	if err != nil {
+		logger.Log.Errorf("Ошибка обновления подписки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+// DeleteWebhook godoc
+// @Summary Удаление подписки на вебхуки
+// @Description Удаляет подписку вместе с историей доставок.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {object} models.MessageResponse "Подписка удалена"
+// @Failure 404 {object} models.ErrorResponse "Подписка не найдена"
+// @Router /webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	logger.Log.Infof("Удаление подписки на вебхуки id: %s", c.Param("id"))
+	subscriptionID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	if err := services.DeleteWebhook(database.DB, subscriptionID); err != nil {
+		logger.Log.Errorf("Ошибка удаления подписки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Подписка удалена"})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary История доставок вебхука
+// @Description Возвращает доставки событий подписчику, новые первыми: статус, число попыток, код ответа и последнюю ошибку. status=dead — журнал недоставленных событий. Доставленные хранятся 7 дней, недоставленные — 30.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param status query string false "Статус: pending, delivered или dead"
+// @Param page query int false "Номер страницы" default(1)
+// @Param pageSize query int false "Размер страницы" default(20)
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} models.ErrorResponse "Некорректный статус"
+// @Failure 404 {object} models.ErrorResponse "Подписка не найдена"
+// @Router /webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	logger.Log.Infof("Получение истории доставок подписки id: %s", c.Param("id"))
+	subscriptionID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	deliveries, err := services.ListDeliveries(database.DB, subscriptionID, c.Query("status"), page, pageSize)
+	if err != nil {
+		logger.Log.Errorf("Ошибка получения истории доставок: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RetryWebhookDelivery godoc
+// @Summary Повторная отправка доставки
+// @Description Возвращает доставку (например, из журнала недоставленных) в очередь с обнулённым счётчиком попыток.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param deliveryId path int true "ID доставки"
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 404 {object} models.ErrorResponse "Доставка не найдена"
+// @Router /webhooks/{id}/deliveries/{deliveryId}/retry [post]
+func RetryWebhookDelivery(c *gin.Context) {
+	logger.Log.Infof("Повторная отправка доставки %s подписки id: %s", c.Param("deliveryId"), c.Param("id"))
+	subscriptionID, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrDeliveryNotFound.Error()})
+		return
+	}
+
+	delivery, err := services.RetryDelivery(database.DB, subscriptionID, uint(deliveryID))
+	if err != nil {
+		logger.Log.Errorf("Ошибка повторной отправки: %v", err)
+		c.JSON(errorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// parseWebhookID разбирает ID подписки из пути. При некорректном значении отвечает 404.
+func parseWebhookID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: services.ErrWebhookNotFound.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -1,40 +1,135 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
+
+	"songs/internal/search"
+
+	"gorm.io/gorm"
 )
 
 type Song struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	ArtistID    uint      `gorm:"index" json:"artistId"`
-	Artist      Artist    `gorm:"foreignKey:ArtistID" json:"artist"`
-	Song        string    `gorm:"not null" json:"song"`
-	ReleaseDate time.Time `json:"releaseDate" example:"2025-01-16"`
-	Text        string    `json:"text"`
-	Link        string    `json:"link"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	ArtistID    uint         `gorm:"index" json:"artistId"`
+	Artist      Artist       `gorm:"foreignKey:ArtistID" json:"artist"`
+	Song        string       `gorm:"not null" json:"song"`
+	ReleaseDate *PartialDate `json:"releaseDate" swaggertype:"string" example:"2025-01-16"`
+	Text        string       `json:"text"`
+	Link        string       `json:"link"`
+	Lang        string       `gorm:"size:16" json:"lang,omitempty" example:"ru"`
+	SearchKey   string       `gorm:"not null;default:''" json:"-"`
+	Links       []SongLink   `gorm:"foreignKey:SongID" json:"links"`
+	Credits     []SongCredit `gorm:"foreignKey:SongID" json:"credits,omitempty"`
+	Genres      []Genre      `gorm:"many2many:song_genres;constraint:OnDelete:CASCADE" json:"genres,omitempty"`
+	Tags        []Tag        `gorm:"many2many:song_tags;constraint:OnDelete:CASCADE" json:"tags,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+// BeforeSave обновляет ключ нечёткого поиска по названию песни.
+func (s *Song) BeforeSave(tx *gorm.DB) error {
+	s.SearchKey = search.Key(s.Song)
+	return nil
 }
 
 type Artist struct {
+	ID        uint          `gorm:"primaryKey" json:"id"`
+	Name      string        `gorm:"uniqueIndex;not null" json:"group"`
+	SearchKey string        `gorm:"not null;default:''" json:"-"`
+	Aliases   []ArtistAlias `gorm:"foreignKey:ArtistID" json:"aliases,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// BeforeSave обновляет ключ нечёткого поиска по имени артиста.
+func (a *Artist) BeforeSave(tx *gorm.DB) error {
+	a.SearchKey = search.Key(a.Name)
+	return nil
+}
+
+// ArtistAlias — другое написание имени артиста («Beatles» для «The Beatles»).
+// По псевдониму артист находится при добавлении песен и в фильтре group.
+type ArtistAlias struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"uniqueIndex;not null" json:"group"`
+	ArtistID  uint      `gorm:"index;not null" json:"artistId"`
+	Artist    Artist    `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Name      string    `gorm:"uniqueIndex;not null" json:"name" example:"Beatles"`
+	SearchKey string    `gorm:"not null;default:''" json:"-"`
 	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// BeforeSave обновляет ключ нечёткого поиска по псевдониму.
+func (a *ArtistAlias) BeforeSave(tx *gorm.DB) error {
+	a.SearchKey = search.Key(a.Name)
+	return nil
+}
+
+type ArtistAliasInput struct {
+	Name string `json:"name" binding:"required" example:"Beatles"`
+}
+
+type ArtistMergeInput struct {
+	DuplicateID uint `json:"duplicateId" binding:"required" example:"2"`
+}
+
+type ArtistMergeResult struct {
+	Artist     Artist `json:"artist"`
+	MovedSongs int    `json:"movedSongs" example:"12"`
 }
 
 type SongUpdate struct {
-	GroupName   *string    `json:"group,omitempty"`
-	Song        *string    `json:"song,omitempty"`
-	ReleaseDate *time.Time `json:"releaseDate,omitempty" example:"2025-01-16"`
-	Text        *string    `json:"text,omitempty"`
-	Link        *string    `json:"link,omitempty"`
+	// Новое имя основного исполнителя: меняется во всех его песнях.
+	GroupName *string `json:"group,omitempty" example:"Muse"`
+	// Перенос песни к другому артисту (создаётся, если его нет); "A feat. B"
+	// пересобирает основного и приглашённых исполнителей.
+	MoveTo      *string      `json:"moveTo,omitempty" example:"Rihanna feat. Drake"`
+	Song        *string      `json:"song,omitempty"`
+	ReleaseDate *PartialDate `json:"releaseDate,omitempty" swaggertype:"string" example:"2025-01"` // "" сбрасывает дату
+	Text        *string      `json:"text,omitempty"`
+	Link        *string      `json:"link,omitempty"`
+	Lang        *string      `json:"lang,omitempty" example:"ru"`
 }
 
 type SongDetail struct {
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+	ReleaseDate string   `json:"releaseDate"`
+	Text        string   `json:"text"`
+	Link        string   `json:"link"`
+	Links       []string `json:"links,omitempty"`
+}
+
+type SongLink struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SongID    uint      `gorm:"uniqueIndex:idx_song_links_song_url;not null" json:"songId"`
+	Song      *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"-"`
+	Platform  string    `gorm:"index;size:32;not null" json:"platform" example:"youtube"`
+	Kind      string    `gorm:"size:32;not null" json:"kind" example:"video"`
+	URL       string    `gorm:"uniqueIndex:idx_song_links_song_url;not null" json:"url" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SongCredit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SongID    uint      `gorm:"uniqueIndex:idx_song_credits_song_artist_role;not null" json:"songId"`
+	Song      *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"-"`
+	ArtistID  uint      `gorm:"uniqueIndex:idx_song_credits_song_artist_role;index;not null" json:"artistId"`
+	Artist    Artist    `gorm:"foreignKey:ArtistID" json:"artist"`
+	Role      string    `gorm:"uniqueIndex:idx_song_credits_song_artist_role;size:16;not null" json:"role" example:"featured"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SongCreditInput struct {
+	GroupName string `json:"group" binding:"required" example:"Rihanna"`
+	Role      string `json:"role" binding:"required" example:"featured"`
+}
+
+type SongLinkInput struct {
+	URL  string `json:"url" binding:"required" example:"https://youtu.be/Xsp3_a-PMTw?si=abc"`
+	Kind string `json:"kind,omitempty" example:"official_video"`
 }
 
 type ErrorResponse struct {
@@ -44,3 +139,475 @@ type ErrorResponse struct {
 type MessageResponse struct {
 	Message string `json:"message"`
 }
+
+type ImportJob struct {
+	ID         string            `json:"id" example:"3f9c2a7b1e4d8c60"`
+	Status     string            `json:"status" example:"running"`
+	Format     string            `json:"format" example:"csv"`
+	DryRun     bool              `json:"dryRun"`
+	Enrich     bool              `json:"enrich"`
+	Total      int               `json:"total"`
+	Processed  int               `json:"processed"`
+	Created    int               `json:"created"`
+	Updated    int               `json:"updated"`
+	Failed     int               `json:"failed"`
+	Errors     []ImportRowResult `json:"errors"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt *time.Time        `json:"finishedAt,omitempty"`
+}
+
+type ImportRowResult struct {
+	Line    int    `json:"line"`
+	Group   string `json:"group"`
+	Song    string `json:"song"`
+	Action  string `json:"action,omitempty" example:"created"`
+	SongID  uint   `json:"songId,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+}
+
+type BatchRequest struct {
+	Mode       string           `json:"mode" example:"atomic"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+type BatchOperation struct {
+	Op    string      `json:"op" example:"patch"`
+	ID    uint        `json:"id,omitempty"`
+	Group string      `json:"group,omitempty"`
+	Song  string      `json:"song,omitempty"`
+	Data  *SongUpdate `json:"data,omitempty"`
+}
+
+type BatchItemResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Status int    `json:"status" example:"200"`
+	Song   *Song  `json:"song,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Mode    string            `json:"mode" example:"atomic"`
+	Success bool              `json:"success"`
+	Results []BatchItemResult `json:"results"`
+}
+
+type LyricsSection struct {
+	Type   string   `json:"type" example:"chorus"`
+	Index  int      `json:"index" example:"2"`
+	Number int      `json:"number" example:"1"`
+	Label  string   `json:"label,omitempty" example:"Chorus"`
+	Lines  []string `json:"lines"`
+}
+
+type SongTextResponse struct {
+	Lang     string           `json:"lang,omitempty" example:"en"`
+	Verses   []string         `json:"verses"`
+	Sections []LyricsSection  `json:"sections"`
+	Aligned  []AlignedSection `json:"aligned,omitempty"`
+}
+
+// AlignedSection — секция оригинала и соответствующая ей секция перевода для двуязычного показа.
+type AlignedSection struct {
+	Index       int      `json:"index"`
+	Type        string   `json:"type" example:"chorus"`
+	Original    []string `json:"original"`
+	Translation []string `json:"translation"`
+}
+
+type SongTranslation struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	SongID    uint      `gorm:"uniqueIndex:idx_song_translations_song_lang;not null" json:"songId"`
+	Song      *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"-"`
+	Lang      string    `gorm:"uniqueIndex:idx_song_translations_song_lang;size:16;not null" json:"lang" example:"en"`
+	Original  bool      `gorm:"-" json:"original"`
+	Text      string    `gorm:"not null" json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type TranslationInput struct {
+	Text string `json:"text" binding:"required"`
+}
+
+type SyncedLyrics struct {
+	ID        uint         `gorm:"primaryKey" json:"-"`
+	SongID    uint         `gorm:"uniqueIndex;not null" json:"songId"`
+	Song      *Song        `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"-"`
+	Enhanced  bool         `json:"enhanced"`
+	Lines     []SyncedLine `gorm:"serializer:json;type:jsonb;not null" json:"lines"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// SyncedLine — строка синхронизированного текста. Время в миллисекундах от начала трека.
+type SyncedLine struct {
+	Time  int64        `json:"time" example:"12340"`
+	Text  string       `json:"text" example:"Ooh baby, don't you know I suffer?"`
+	Words []SyncedWord `json:"words,omitempty"`
+}
+
+type SyncedWord struct {
+	Time int64  `json:"time" example:"12340"`
+	Text string `json:"text" example:"Ooh"`
+}
+
+type SyncedLineAt struct {
+	Index    int        `json:"index"`
+	Line     SyncedLine `json:"line"`
+	NextTime *int64     `json:"nextTime,omitempty"`
+}
+
+type Album struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Title       string       `gorm:"not null;index" json:"title" example:"Black Holes and Revelations"`
+	ArtistID    uint         `gorm:"index" json:"artistId"`
+	Artist      Artist       `gorm:"foreignKey:ArtistID" json:"artist"`
+	ReleaseDate *PartialDate `json:"releaseDate" swaggertype:"string" example:"2006-07-03"`
+	CoverURL    string       `json:"coverUrl" example:"https://example.com/cover.jpg"`
+	Type        string       `gorm:"size:16;not null;default:album" json:"type" example:"album"`
+	Tracks      []AlbumTrack `gorm:"foreignKey:AlbumID" json:"tracks,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+}
+
+type AlbumTrack struct {
+	AlbumID  uint   `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Album    *Album `gorm:"foreignKey:AlbumID;constraint:OnDelete:CASCADE" json:"-"`
+	Position int    `gorm:"primaryKey;autoIncrement:false" json:"position" example:"1"`
+	SongID   uint   `gorm:"index;not null" json:"songId"`
+	Song     *Song  `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"song,omitempty"`
+}
+
+type AlbumInput struct {
+	Title       string       `json:"title" binding:"required" example:"Black Holes and Revelations"`
+	GroupName   string       `json:"group" binding:"required" example:"Muse"`
+	ReleaseDate *PartialDate `json:"releaseDate,omitempty" swaggertype:"string" example:"2006-07-03"`
+	CoverURL    string       `json:"coverUrl,omitempty"`
+	Type        string       `json:"type,omitempty" example:"album"`
+	SongIDs     []uint       `json:"songIds,omitempty"`
+}
+
+type AlbumUpdate struct {
+	Title       *string      `json:"title,omitempty"`
+	GroupName   *string      `json:"group,omitempty"`
+	ReleaseDate *PartialDate `json:"releaseDate,omitempty" swaggertype:"string" example:"2006-07-03"` // "" сбрасывает дату
+	CoverURL    *string      `json:"coverUrl,omitempty"`
+	Type        *string      `json:"type,omitempty" example:"ep"`
+}
+
+type AlbumTracksInput struct {
+	SongIDs []uint `json:"songIds"`
+}
+
+type Genre struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;not null" json:"name" example:"Alternative Rock"`
+	ParentID  *uint     `gorm:"index" json:"parentId,omitempty"`
+	Parent    *Genre    `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL" json:"-"`
+	Children  []Genre   `gorm:"-" json:"children,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;size:64;not null" json:"name" example:"road trip"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type GenreInput struct {
+	Name     string `json:"name" binding:"required" example:"Alternative Rock"`
+	ParentID *uint  `json:"parentId,omitempty" example:"1"`
+}
+
+type GenreUpdate struct {
+	Name *string `json:"name,omitempty"`
+	// 0 делает жанр корневым.
+	ParentID *uint `json:"parentId,omitempty"`
+}
+
+type SongGenresInput struct {
+	GenreIDs []uint `json:"genreIds"`
+}
+
+type SongTagsInput struct {
+	Tags []string `json:"tags" example:"road trip,summer"`
+}
+
+type FacetCount struct {
+	ID    uint   `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type YearFacet struct {
+	Year  int   `json:"year"`
+	Count int64 `json:"count"`
+}
+
+type FacetsResponse struct {
+	Total   int64        `json:"total"`
+	Genres  []FacetCount `json:"genres"`
+	Tags    []FacetCount `json:"tags"`
+	Artists []FacetCount `json:"artists"`
+	Years   []YearFacet  `json:"years"`
+}
+
+type Playlist struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	OwnerID     string         `gorm:"index;size:64;not null" json:"ownerId" example:"user-42"`
+	Title       string         `gorm:"not null" json:"title" example:"В дорогу"`
+	Description string         `json:"description"`
+	Public      bool           `gorm:"not null;default:false" json:"public"`
+	ShareToken  *string        `gorm:"uniqueIndex;size:32" json:"shareToken,omitempty"`
+	Items       []PlaylistItem `gorm:"foreignKey:PlaylistID" json:"items,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+type PlaylistItem struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PlaylistID uint      `gorm:"index:idx_playlist_items_position,priority:1;not null" json:"playlistId"`
+	Playlist   *Playlist `gorm:"foreignKey:PlaylistID;constraint:OnDelete:CASCADE" json:"-"`
+	Position   float64   `gorm:"index:idx_playlist_items_position,priority:2;not null" json:"-"`
+	Index      int       `gorm:"-" json:"index"`
+	SongID     uint      `gorm:"index;not null" json:"songId"`
+	Song       *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"song,omitempty"`
+	AddedAt    time.Time `gorm:"autoCreateTime" json:"addedAt"`
+}
+
+type PlaylistInput struct {
+	Title       string `json:"title" binding:"required" example:"В дорогу"`
+	Description string `json:"description,omitempty"`
+	Public      bool   `json:"public"`
+}
+
+type PlaylistUpdate struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Public      *bool   `json:"public,omitempty"`
+}
+
+type PlaylistItemInput struct {
+	SongID uint `json:"songId" binding:"required" example:"1"`
+	// Индекс вставки с нуля. Если не передан, песня добавляется в конец.
+	Index *int `json:"index,omitempty" example:"0"`
+}
+
+type PlaylistMoveInput struct {
+	Index *int `json:"index" binding:"required" example:"0"`
+}
+
+type ShareTokenResponse struct {
+	ShareToken string `json:"shareToken"`
+}
+
+type SongStats struct {
+	SongID            uint      `gorm:"primaryKey;autoIncrement:false" json:"songId"`
+	Song              *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"-"`
+	ListenCount       int64     `gorm:"not null;default:0" json:"listenCount"`
+	ViewCount         int64     `gorm:"not null;default:0" json:"viewCount"`
+	FavoriteCount     int64     `gorm:"not null;default:0" json:"favoriteCount"`
+	TrendingScore     float64   `gorm:"not null;default:0" json:"-"`
+	TrendingUpdatedAt time.Time `json:"-"`
+}
+
+func (SongStats) TableName() string {
+	return "song_stats"
+}
+
+type Favorite struct {
+	UserID    string    `gorm:"primaryKey;size:64" json:"userId"`
+	SongID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"songId"`
+	Song      *Song     `gorm:"foreignKey:SongID;constraint:OnDelete:CASCADE" json:"song,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SongArchive хранит данные песни, которые при удалении стираются каскадом:
+// переводы, синхронизированный текст, места в альбомах и плейлистах, избранное
+// и статистику. RestoreSong возвращает их вместе с песней.
+type SongArchive struct {
+	SongID    uint            `gorm:"primaryKey;autoIncrement:false"`
+	Data      SongArchiveData `gorm:"serializer:json;type:jsonb;not null"`
+	DeletedAt time.Time       `gorm:"not null"`
+}
+
+type SongArchiveData struct {
+	Translations  []SongTranslation      `json:"translations,omitempty"`
+	SyncedLyrics  *SyncedLyrics          `json:"syncedLyrics,omitempty"`
+	AlbumTracks   []ArchivedAlbumTrack   `json:"albumTracks,omitempty"`
+	PlaylistItems []ArchivedPlaylistItem `json:"playlistItems,omitempty"`
+	Favorites     []Favorite             `json:"favorites,omitempty"`
+	Stats         *ArchivedSongStats     `json:"stats,omitempty"`
+}
+
+type ArchivedAlbumTrack struct {
+	AlbumID  uint `json:"albumId"`
+	Position int  `json:"position"`
+}
+
+type ArchivedPlaylistItem struct {
+	ID         uint      `json:"id"`
+	PlaylistID uint      `json:"playlistId"`
+	Position   float64   `json:"position"`
+	AddedAt    time.Time `json:"addedAt"`
+}
+
+type ArchivedSongStats struct {
+	ListenCount       int64     `json:"listenCount"`
+	ViewCount         int64     `json:"viewCount"`
+	FavoriteCount     int64     `json:"favoriteCount"`
+	TrendingScore     float64   `json:"trendingScore"`
+	TrendingUpdatedAt time.Time `json:"trendingUpdatedAt"`
+}
+
+type PlayInput struct {
+	Kind string `json:"kind" example:"listen"`
+}
+
+type SongRanking struct {
+	Rank  int       `json:"rank"`
+	Score float64   `json:"score"`
+	Song  Song      `json:"song"`
+	Stats SongStats `json:"stats"`
+}
+
+type SongSearchResult struct {
+	Score float64 `json:"score" example:"0.83"`
+	Song  Song    `json:"song"`
+}
+
+type SongSearchResponse struct {
+	Query      string             `json:"query"`
+	Results    []SongSearchResult `json:"results"`
+	DidYouMean string             `json:"didYouMean,omitempty" example:"Кино"`
+}
+
+// Suggestion — вариант автодополнения: артист или песня.
+type Suggestion struct {
+	Type     string `json:"type" example:"song"`
+	ID       uint   `json:"id" example:"1"`
+	Name     string `json:"name" example:"Группа крови"`
+	Artist   string `json:"artist,omitempty" example:"Кино"`
+	ArtistID uint   `json:"artistId,omitempty" example:"1"`
+}
+
+// Event — событие каталога. События хранятся в таблице catalog_events (outbox):
+// строка пишется в той же транзакции, что и изменение, а порядковый номер Seq
+// задаёт порядок для ленты изменений. Те же события получают подписчики вебхуков.
+type Event struct {
+	Seq        uint64          `gorm:"primaryKey" json:"seq" example:"1042"`
+	ID         string          `gorm:"size:32;uniqueIndex;not null" json:"id" example:"9b2f6c1d4e8a7f30"`
+	Type       string          `gorm:"size:64;not null" json:"type" example:"song.updated"`
+	EntityID   uint            `gorm:"not null;index:idx_catalog_events_entity,priority:2" json:"entityId" example:"1"`
+	EntityType string          `gorm:"size:16;not null;index:idx_catalog_events_entity,priority:1" json:"entityType" example:"song"`
+	OccurredAt time.Time       `gorm:"not null" json:"occurredAt"`
+	Data       json.RawMessage `gorm:"type:jsonb;not null" json:"data" swaggertype:"object"`
+}
+
+func (Event) TableName() string {
+	return "catalog_events"
+}
+
+type ChangesResponse struct {
+	Events []Event `json:"events"`
+	// Значение since для следующего запроса.
+	NextSince uint64 `json:"nextSince" example:"1042"`
+	// Есть ещё события сверх limit: следующий запрос вернёт их сразу.
+	HasMore bool `json:"hasMore"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required" example:"{ song(id: 1) { song artist { name } verses(page: 1) { verses total } } }"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse описывает ответ GraphQL для документации: data с результатом
+// запроса и errors с ошибками отдельных полей.
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []GraphQLError         `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message string        `json:"message" example:"Песня не найдена"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// StringList хранится в БД строкой через запятую: для коротких списков вроде
+// типов событий, где отдельная таблица избыточна.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("неподдерживаемый тип списка: %T", value)
+	}
+	*l = StringList{}
+	if raw != "" {
+		*l = strings.Split(raw, ",")
+	}
+	return nil
+}
+
+func (StringList) GormDataType() string {
+	return "text"
+}
+
+// WebhookSubscription — подписка на события каталога. Пустой список событий — все события.
+type WebhookSubscription struct {
+	ID  uint   `gorm:"primaryKey" json:"id"`
+	URL string `gorm:"not null" json:"url" example:"https://indexer.example.com/hooks/songs"`
+	// Маски вида song.* подходят под все события песен.
+	Events StringList `gorm:"not null;default:''" json:"events" swaggertype:"array,string" example:"song.created,song.deleted"`
+	// Секрет для подписи HMAC-SHA256. Возвращается только при создании и смене секрета.
+	Secret    string    `gorm:"not null" json:"secret,omitempty"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type WebhookInput struct {
+	URL    string   `json:"url" binding:"required" example:"https://indexer.example.com/hooks/songs"`
+	Events []string `json:"events,omitempty" example:"song.created,song.deleted"`
+	// Если не передан, генерируется случайный.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookUpdate struct {
+	URL    *string   `json:"url,omitempty"`
+	Events *[]string `json:"events,omitempty"`
+	Secret *string   `json:"secret,omitempty"`
+	Active *bool     `json:"active,omitempty"`
+}
+
+// WebhookDelivery — доставка одного события одному подписчику. Доставки, исчерпавшие
+// попытки, остаются со статусом dead и служат журналом недоставленных событий.
+type WebhookDelivery struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint                `gorm:"index;not null" json:"subscriptionId"`
+	Subscription   WebhookSubscription `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	EventID        string              `gorm:"size:32;not null" json:"eventId" example:"9b2f6c1d4e8a7f30"`
+	EventType      string              `gorm:"size:64;not null" json:"eventType" example:"song.updated"`
+	Payload        json.RawMessage     `gorm:"type:jsonb;not null" json:"payload" swaggertype:"object"`
+	Status         string              `gorm:"size:16;not null;index:idx_webhook_deliveries_due,priority:1" json:"status" example:"pending"`
+	Attempts       int                 `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time           `gorm:"index:idx_webhook_deliveries_due,priority:2" json:"nextAttemptAt"`
+	ResponseStatus int                 `json:"responseStatus,omitempty" example:"502"`
+	LastError      string              `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time          `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	UpdatedAt      time.Time           `json:"updatedAt"`
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Точность даты релиза: известен только год, год и месяц или точный день.
+const (
+	PrecisionYear  = "year"
+	PrecisionMonth = "month"
+	PrecisionDay   = "day"
+)
+
+// Форматы, в которых даты приходят из внешнего API, импорта и запросов.
+// Порядок важен: первым подходит самый точный формат. Ведущие нули в дне и
+// месяце необязательны.
+var partialDateLayouts = []struct {
+	layout    string
+	precision string
+}{
+	{"2006-1-2", PrecisionDay},
+	{"2.1.2006", PrecisionDay},
+	{"2006/1/2", PrecisionDay},
+	{"2 January 2006", PrecisionDay},
+	{"January 2, 2006", PrecisionDay},
+	{"2006-1", PrecisionMonth},
+	{"1.2006", PrecisionMonth},
+	{"2006/1", PrecisionMonth},
+	{"January 2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// PartialDate — дата без времени и часового пояса с точностью до года, месяца
+// или дня. В JSON и в БД записывается строкой «2006», «2006-07» или «2006-07-16»,
+// поэтому строки сортируются хронологически, а менее точная дата идёт раньше
+// более точной внутри своего периода. Неизвестная дата — nil (null в JSON и БД).
+type PartialDate struct {
+	Year  int
+	Month int // 0, если известен только год
+	Day   int // 0, если известны только год и месяц
+}
+
+// ParsePartialDate разбирает дату в одном из поддерживаемых форматов: ISO
+// («2006», «2006-07», «2006-07-16», RFC 3339), формат внешнего API («16.07.2006»,
+// «07.2006»), через косую черту и с английскими названиями месяцев.
+func ParsePartialDate(value string) (PartialDate, error) {
+	value = strings.TrimSpace(value)
+	// Для меток времени берём дату в их собственном часовом поясе, без перевода.
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return PartialDate{Year: parsed.Year(), Month: int(parsed.Month()), Day: parsed.Day()}, nil
+	}
+	for _, format := range partialDateLayouts {
+		parsed, err := time.Parse(format.layout, value)
+		if err != nil {
+			continue
+		}
+		date := PartialDate{Year: parsed.Year()}
+		if format.precision != PrecisionYear {
+			date.Month = int(parsed.Month())
+		}
+		if format.precision == PrecisionDay {
+			date.Day = parsed.Day()
+		}
+		if date.Year < 1 {
+			break
+		}
+		return date, nil
+	}
+	return PartialDate{}, fmt.Errorf("некорректная дата релиза: %s", value)
+}
+
+// ParsePartialDateUpdate разбирает дату релиза из изменения песни или альбома:
+// пустая строка означает сброс даты и возвращается как нулевая PartialDate.
+func ParsePartialDateUpdate(value string) (PartialDate, error) {
+	if strings.TrimSpace(value) == "" {
+		return PartialDate{}, nil
+	}
+	return ParsePartialDate(value)
+}
+
+// IsZero сообщает, что дата не задана: так в изменениях передаётся сброс даты.
+func (d PartialDate) IsZero() bool {
+	return d == PartialDate{}
+}
+
+// Precision возвращает точность даты: year, month или day.
+func (d PartialDate) Precision() string {
+	switch {
+	case d.Day != 0:
+		return PrecisionDay
+	case d.Month != 0:
+		return PrecisionMonth
+	}
+	return PrecisionYear
+}
+
+// String возвращает дату в ISO-виде с её точностью.
+func (d PartialDate) String() string {
+	switch d.Precision() {
+	case PrecisionDay:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	case PrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	}
+	return fmt.Sprintf("%04d", d.Year)
+}
+
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return json.Marshal("")
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *PartialDate) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("дата релиза должна быть строкой: %s", data)
+	}
+	parsed, err := ParsePartialDateUpdate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value сохраняет дату в БД строкой; nil-указатель и нулевая дата сохраняются как NULL.
+func (d PartialDate) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d *PartialDate) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип даты релиза: %T", value)
+	}
+	parsed, err := ParsePartialDate(raw)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// GormDataType задаёт тип колонки: строки ISO длиной до 10 символов.
+func (PartialDate) GormDataType() string {
+	return "varchar(10)"
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParsePartialDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    PartialDate
+		wantErr bool
+	}{
+		{value: "2006", want: PartialDate{Year: 2006}},
+		{value: "07.2006", want: PartialDate{Year: 2006, Month: 7}},
+		{value: "2006-07", want: PartialDate{Year: 2006, Month: 7}},
+		{value: "2006-7-1", want: PartialDate{Year: 2006, Month: 7, Day: 1}},
+		{value: "16.07.2006", want: PartialDate{Year: 2006, Month: 7, Day: 16}},
+		{value: "2006-07-16T23:30:00-05:00", want: PartialDate{Year: 2006, Month: 7, Day: 16}},
+		{value: "July 2006", want: PartialDate{Year: 2006, Month: 7}},
+		{value: "0000", wantErr: true},
+		{value: "2006-13", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePartialDate(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePartialDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePartialDate(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSongUpdateReleaseDate(t *testing.T) {
+	tests := []struct {
+		body      string
+		wantSet   bool
+		wantClear bool
+	}{
+		{body: `{}`},
+		{body: `{"releaseDate":null}`},
+		{body: `{"releaseDate":""}`, wantSet: true, wantClear: true},
+		{body: `{"releaseDate":"2006-07"}`, wantSet: true},
+	}
+	for _, tt := range tests {
+		var update SongUpdate
+		if err := json.Unmarshal([]byte(tt.body), &update); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.body, err)
+			continue
+		}
+		if (update.ReleaseDate != nil) != tt.wantSet {
+			t.Errorf("Unmarshal(%s) ReleaseDate = %v, want set %v", tt.body, update.ReleaseDate, tt.wantSet)
+			continue
+		}
+		if update.ReleaseDate != nil && update.ReleaseDate.IsZero() != tt.wantClear {
+			t.Errorf("Unmarshal(%s) ReleaseDate.IsZero() = %v, want %v", tt.body, update.ReleaseDate.IsZero(), tt.wantClear)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package search
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Размер блока слов. Правка сдвигает слова только внутри своего блока, а
+// блок, выросший вдвое, делится пополам.
+const prefixBlockSize = 512
+
+// PrefixIndex находит записи, у которых имя или одно из слов имени начинается
+// с запроса. Ключи имён приводятся через Key, поэтому «кин» находит «Kino».
+// Безопасен для конкурентного использования.
+type PrefixIndex[T any] struct {
+	mu   sync.RWMutex
+	docs map[string]prefixDoc[T]
+	// Слова, отсортированные по ключу и id и разбитые на идущие подряд блоки:
+	// вставка и удаление находят место двоичным поиском и не сдвигают весь индекс.
+	blocks [][]prefixTerm
+}
+
+// PrefixEntry — запись для заполнения индекса целиком через NewPrefixIndexFrom.
+type PrefixEntry[T any] struct {
+	ID     string
+	Name   string
+	Weight float64
+	Value  T
+}
+
+type prefixDoc[T any] struct {
+	key    string
+	weight float64
+	value  T
+}
+
+// prefixTerm — суффикс ключа имени, начинающийся с границы слова.
+type prefixTerm struct {
+	key   string
+	id    string
+	start bool
+}
+
+// Ранги совпадения: имя совпадает с запросом, начинается с него или с него начинается слово в имени.
+const (
+	matchWord = iota + 1
+	matchStart
+	matchExact
+)
+
+func NewPrefixIndex[T any]() *PrefixIndex[T] {
+	return &PrefixIndex[T]{docs: make(map[string]prefixDoc[T])}
+}
+
+// NewPrefixIndexFrom строит индекс по записям сразу: слова сортируются один
+// раз, а не вставляются по одному, как при Put. Повтор id заменяет запись.
+func NewPrefixIndexFrom[T any](entries []PrefixEntry[T]) *PrefixIndex[T] {
+	ix := &PrefixIndex[T]{docs: make(map[string]prefixDoc[T], len(entries))}
+	for _, entry := range entries {
+		if key := Key(entry.Name); key != "" {
+			ix.docs[entry.ID] = prefixDoc[T]{key: key, weight: entry.Weight, value: entry.Value}
+		} else {
+			delete(ix.docs, entry.ID)
+		}
+	}
+	var terms []prefixTerm
+	for id, doc := range ix.docs {
+		terms = append(terms, wordTerms(doc.key, id)...)
+	}
+	slices.SortFunc(terms, compareTerms)
+	for start := 0; start < len(terms); start += prefixBlockSize {
+		end := min(start+prefixBlockSize, len(terms))
+		// Ограничение ёмкости: вставка в блок не должна затирать следующий.
+		ix.blocks = append(ix.blocks, terms[start:end:end])
+	}
+	return ix
+}
+
+// Put добавляет или заменяет запись id. Записи с большим weight выше в выдаче.
+func (ix *PrefixIndex[T]) Put(id, name string, weight float64, value T) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.removeLocked(id)
+	key := Key(name)
+	if key == "" {
+		return
+	}
+	ix.docs[id] = prefixDoc[T]{key: key, weight: weight, value: value}
+	for _, term := range wordTerms(key, id) {
+		ix.insertLocked(term)
+	}
+}
+
+// Remove удаляет запись id, если она есть.
+func (ix *PrefixIndex[T]) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.removeLocked(id)
+}
+
+// Len возвращает число записей в индексе.
+func (ix *PrefixIndex[T]) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Search возвращает до limit записей, подходящих под запрос: сначала точные
+// совпадения, затем имена, начинающиеся с запроса, затем совпадения по слову;
+// внутри группы — по убыванию веса и по длине имени.
+func (ix *PrefixIndex[T]) Search(query string, limit int) []T {
+	key := Key(query)
+	if key == "" || limit < 1 {
+		return nil
+	}
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	ranks := make(map[string]int)
+	b := sort.Search(len(ix.blocks), func(b int) bool { return lastTerm(ix.blocks[b]).key >= key })
+	i := 0
+	if b < len(ix.blocks) {
+		block := ix.blocks[b]
+		i = sort.Search(len(block), func(i int) bool { return block[i].key >= key })
+	}
+scan:
+	for ; b < len(ix.blocks); b, i = b+1, 0 {
+		for _, term := range ix.blocks[b][i:] {
+			if !strings.HasPrefix(term.key, key) {
+				break scan
+			}
+			rank := matchWord
+			if term.start {
+				rank = matchStart
+				if term.key == key {
+					rank = matchExact
+				}
+			}
+			if rank > ranks[term.id] {
+				ranks[term.id] = rank
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(ranks))
+	for id := range ranks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := ix.docs[ids[i]], ix.docs[ids[j]]
+		switch {
+		case ranks[ids[i]] != ranks[ids[j]]:
+			return ranks[ids[i]] > ranks[ids[j]]
+		case a.weight != b.weight:
+			return a.weight > b.weight
+		case len(a.key) != len(b.key):
+			return len(a.key) < len(b.key)
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	values := make([]T, len(ids))
+	for i, id := range ids {
+		values[i] = ix.docs[id].value
+	}
+	return values
+}
+
+// removeLocked удаляет запись и её слова. Слова записи вычисляются по её
+// ключу, поэтому весь индекс не просматривается.
+func (ix *PrefixIndex[T]) removeLocked(id string) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	delete(ix.docs, id)
+	for _, term := range wordTerms(doc.key, id) {
+		if len(ix.blocks) == 0 {
+			return
+		}
+		b, i, found := ix.locate(term)
+		if !found {
+			continue
+		}
+		ix.blocks[b] = slices.Delete(ix.blocks[b], i, i+1)
+		if len(ix.blocks[b]) == 0 {
+			ix.blocks = slices.Delete(ix.blocks, b, b+1)
+		}
+	}
+}
+
+func (ix *PrefixIndex[T]) insertLocked(term prefixTerm) {
+	if len(ix.blocks) == 0 {
+		ix.blocks = [][]prefixTerm{{term}}
+		return
+	}
+	b, i, _ := ix.locate(term)
+	block := slices.Insert(ix.blocks[b], i, term)
+	if len(block) < 2*prefixBlockSize {
+		ix.blocks[b] = block
+		return
+	}
+	half := len(block) / 2
+	ix.blocks[b] = block[:half:half]
+	ix.blocks = slices.Insert(ix.blocks, b+1, block[half:])
+}
+
+// locate возвращает блок, в котором слово находится или должно находиться,
+// и позицию в нём. Индекс не пуст.
+func (ix *PrefixIndex[T]) locate(term prefixTerm) (block, pos int, found bool) {
+	block = sort.Search(len(ix.blocks), func(b int) bool { return compareTerms(lastTerm(ix.blocks[b]), term) >= 0 })
+	if block == len(ix.blocks) {
+		block--
+	}
+	pos, found = slices.BinarySearchFunc(ix.blocks[block], term, compareTerms)
+	return block, pos, found
+}
+
+func lastTerm(block []prefixTerm) prefixTerm {
+	return block[len(block)-1]
+}
+
+// wordTerms возвращает суффиксы ключа, начинающиеся с каждого слова.
+func wordTerms(key, id string) []prefixTerm {
+	var terms []prefixTerm
+	for i := 0; i < len(key); i++ {
+		if i == 0 || key[i-1] == ' ' {
+			terms = append(terms, prefixTerm{key: key[i:], id: id, start: i == 0})
+		}
+	}
+	return terms
+}
+
+func compareTerms(a, b prefixTerm) int {
+	if c := strings.Compare(a.key, b.key); c != 0 {
+		return c
+	}
+	return strings.Compare(a.id, b.id)
+}
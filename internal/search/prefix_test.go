@@ -0,0 +1,98 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPrefixIndexSearch(t *testing.T) {
+	ix := NewPrefixIndex[string]()
+	ix.Put("1", "Kino", 1, "Kino")
+	ix.Put("2", "Kinoproba", 5, "Kinoproba")
+	ix.Put("3", "Группа Кино", 10, "Группа Кино")
+	ix.Put("4", "Muse", 1, "Muse")
+
+	tests := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{"кин", 10, []string{"Kinoproba", "Kino", "Группа Кино"}},
+		{"kino", 10, []string{"Kino", "Kinoproba", "Группа Кино"}},
+		{"КИНО", 1, []string{"Kino"}},
+		{"gruppa k", 10, []string{"Группа Кино"}},
+		{"mu", 10, []string{"Muse"}},
+		{"use", 10, nil},
+		{"!!!", 10, nil},
+		{"kino", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := ix.Search(tt.query, tt.limit); len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q, %d) = %q, want %q", tt.query, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixIndexPutRemove(t *testing.T) {
+	ix := NewPrefixIndex[string]()
+	ix.Put("1", "Kino", 1, "Kino")
+	ix.Put("2", "Muse", 1, "Muse")
+
+	ix.Put("2", "Uprising", 1, "Uprising")
+	if got := ix.Search("muse", 10); len(got) != 0 {
+		t.Errorf("после замены Search(muse) = %q, want пусто", got)
+	}
+	if got := ix.Search("up", 10); !reflect.DeepEqual(got, []string{"Uprising"}) {
+		t.Errorf("после замены Search(up) = %q, want [Uprising]", got)
+	}
+
+	ix.Remove("1")
+	ix.Remove("missing")
+	if got := ix.Search("kino", 10); len(got) != 0 {
+		t.Errorf("после удаления Search(kino) = %q, want пусто", got)
+	}
+
+	ix.Put("2", "!!!", 1, "!!!")
+	if ix.Len() != 0 {
+		t.Errorf("Len() = %d, want 0: имя без букв и цифр удаляет запись", ix.Len())
+	}
+}
+
+func TestPrefixIndexBlocks(t *testing.T) {
+	// Записей больше, чем помещается в два блока: Put делит блоки, и результат
+	// должен совпадать с индексом, построенным целиком.
+	const n = 3000
+	entries := make([]PrefixEntry[string], n)
+	incremental := NewPrefixIndex[string]()
+	for i := range entries {
+		id := fmt.Sprintf("%04d", i)
+		entries[i] = PrefixEntry[string]{ID: id, Name: "song " + id, Value: id}
+		incremental.Put(id, "song "+id, 0, id)
+	}
+	bulk := NewPrefixIndexFrom(entries)
+
+	want := make([]string, 0, 100)
+	for i := 100; i < 200; i++ {
+		want = append(want, fmt.Sprintf("%04d", i))
+	}
+	for name, ix := range map[string]*PrefixIndex[string]{"Put": incremental, "NewPrefixIndexFrom": bulk} {
+		if ix.Len() != n {
+			t.Errorf("%s: Len() = %d, want %d", name, ix.Len(), n)
+		}
+		if got := ix.Search("song 01", n); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Search(song 01) = %d записей, want %d", name, len(got), len(want))
+		}
+		if got := ix.Search("0150", n); !reflect.DeepEqual(got, []string{"0150"}) {
+			t.Errorf("%s: Search(0150) = %q, want [0150]", name, got)
+		}
+	}
+
+	for i := 0; i < n; i += 2 {
+		incremental.Remove(fmt.Sprintf("%04d", i))
+	}
+	got := incremental.Search("song 01", n)
+	if len(got) != 50 || got[0] != "0101" || got[49] != "0199" {
+		t.Errorf("после удаления чётных Search(song 01) = %q", got)
+	}
+}
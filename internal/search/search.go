@@ -0,0 +1,73 @@
+// Package search приводит названия к виду для нечёткого поиска.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Упрощённая транслитерация кириллицы: достаточно, чтобы "Кино" и "Kino"
+// давали один и тот же ключ, а близкие варианты записи различались на пару триграмм.
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+	'щ': "sch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'є': "e", 'ґ': "g",
+}
+
+// Key возвращает ключ для поиска: нижний регистр, без диакритики (é -> e, й -> и),
+// кириллица в латинице, вместо знаков препинания одиночные пробелы.
+func Key(value string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+	if err != nil {
+		stripped = value
+	}
+
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(stripped) {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			b.WriteString(latin)
+			space = false
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space && b.Len() > 0 {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// FoldsOnly сообщает, что Key меняет в value только регистр, диакритику и
+// письменность, а не выбрасывает знаки: в "100%" или "AC/DC" Key заменяет знаки
+// пробелом, и поиск по ключу нашёл бы "1000 Miles" или "ac dc".
+func FoldsOnly(value string) bool {
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && !unicode.Is(unicode.Mn, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// EscapeLike экранирует метасимволы LIKE (% и _), чтобы название вроде "100%"
+// искалось буквально.
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// Contains строит шаблон LIKE для поиска подстроки.
+func Contains(value string) string {
+	return "%" + EscapeLike(value) + "%"
+}
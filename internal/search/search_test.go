@@ -0,0 +1,57 @@
+package search
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"Кино", "kino"},
+		{"Beyoncé", "beyonce"},
+		{"  AC/DC ", "ac dc"},
+		{"Щедрин — Ёлка", "schedrin elka"},
+		{"Йорш", "iorsh"},
+		{"100%", "100"},
+		{"a_b", "a b"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.value); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFoldsOnly(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"Кино", true},
+		{"Beyoncé", true},
+		{"Bohemian Rhapsody", true},
+		{"100%", false},
+		{"a_b", false},
+		{"AC/DC", false},
+	}
+	for _, tt := range tests {
+		if got := FoldsOnly(tt.value); got != tt.want {
+			t.Errorf("FoldsOnly(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"100%", `%100\%%`},
+		{"a_b", `%a\_b%`},
+		{`c:\music`, `%c:\\music%`},
+	}
+	for _, tt := range tests {
+		if got := Contains(tt.value); got != tt.want {
+			t.Errorf("Contains(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
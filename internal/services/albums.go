@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AlbumTypeAlbum  = "album"
+	AlbumTypeEP     = "ep"
+	AlbumTypeSingle = "single"
+)
+
+var (
+	ErrAlbumNotFound    = errors.New("Альбом не найден")
+	ErrInvalidAlbum     = errors.New("Поля title и group обязательны")
+	ErrInvalidAlbumType = errors.New("Некорректный тип альбома, допустимы album, ep, single")
+	ErrInvalidCover     = errors.New("Некорректная ссылка на обложку, ожидается http(s) URL")
+	ErrInvalidTracks    = errors.New("Список треков содержит несуществующие или повторяющиеся песни")
+)
+
+// CreateAlbum сохраняет альбом с треками в переданном порядке. Артист находится
+// по имени или создаётся.
+func CreateAlbum(db *gorm.DB, input models.AlbumInput) (models.Album, error) {
+	if strings.TrimSpace(input.Title) == "" || strings.TrimSpace(input.GroupName) == "" {
+		return models.Album{}, ErrInvalidAlbum
+	}
+	albumType, err := normalizeAlbumType(input.Type)
+	if err != nil {
+		return models.Album{}, err
+	}
+	if err := validateCoverURL(input.CoverURL); err != nil {
+		return models.Album{}, err
+	}
+
+	artist, err := ResolveArtist(db, input.GroupName)
+	if err != nil {
+		return models.Album{}, err
+	}
+
+	album := models.Album{
+		Title:    strings.TrimSpace(input.Title),
+		ArtistID: artist.ID,
+		Artist:   artist,
+		CoverURL: input.CoverURL,
+		Type:     albumType,
+	}
+	if input.ReleaseDate != nil && !input.ReleaseDate.IsZero() {
+		album.ReleaseDate = input.ReleaseDate
+	}
+	if err := db.Omit("Artist", "Tracks").Create(&album).Error; err != nil {
+		return models.Album{}, err
+	}
+	if len(input.SongIDs) > 0 {
+		if _, err := SetAlbumTracks(db, album.ID, input.SongIDs); err != nil {
+			return models.Album{}, err
+		}
+	}
+	return FindAlbum(db, album.ID)
+}
+
+// UpdateAlbum применяет к альбому переданные поля.
+func UpdateAlbum(db *gorm.DB, id uint, input models.AlbumUpdate) (models.Album, error) {
+	var album models.Album
+	if err := db.First(&album, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return album, ErrAlbumNotFound
+		}
+		return album, err
+	}
+
+	if input.Title != nil {
+		if strings.TrimSpace(*input.Title) == "" {
+			return album, ErrInvalidAlbum
+		}
+		album.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.GroupName != nil {
+		if strings.TrimSpace(*input.GroupName) == "" {
+			return album, ErrInvalidAlbum
+		}
+		artist, err := ResolveArtist(db, *input.GroupName)
+		if err != nil {
+			return album, err
+		}
+		album.ArtistID = artist.ID
+	}
+	if input.ReleaseDate != nil {
+		album.ReleaseDate = input.ReleaseDate
+		if input.ReleaseDate.IsZero() {
+			album.ReleaseDate = nil
+		}
+	}
+	if input.CoverURL != nil {
+		if err := validateCoverURL(*input.CoverURL); err != nil {
+			return album, err
+		}
+		album.CoverURL = *input.CoverURL
+	}
+	if input.Type != nil {
+		albumType, err := normalizeAlbumType(*input.Type)
+		if err != nil {
+			return album, err
+		}
+		album.Type = albumType
+	}
+
+	if err := db.Omit("Artist", "Tracks").Save(&album).Error; err != nil {
+		return album, err
+	}
+	return FindAlbum(db, album.ID)
+}
+
+// SetAlbumTracks заменяет треклист альбома: позиции нумеруются с 1 в порядке songIDs.
+func SetAlbumTracks(db *gorm.DB, albumID uint, songIDs []uint) ([]models.AlbumTrack, error) {
+	if err := db.Select("id").First(&models.Album{}, albumID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlbumNotFound
+		}
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(songIDs))
+	for _, id := range songIDs {
+		if seen[id] {
+			return nil, ErrInvalidTracks
+		}
+		seen[id] = true
+	}
+	if len(songIDs) > 0 {
+		var found int64
+		if err := db.Model(&models.Song{}).Where("id IN ?", songIDs).Count(&found).Error; err != nil {
+			return nil, err
+		}
+		if int(found) != len(songIDs) {
+			return nil, ErrInvalidTracks
+		}
+	}
+
+	if err := db.Where("album_id = ?", albumID).Delete(&models.AlbumTrack{}).Error; err != nil {
+		return nil, err
+	}
+	tracks := make([]models.AlbumTrack, len(songIDs))
+	for i, id := range songIDs {
+		tracks[i] = models.AlbumTrack{AlbumID: albumID, Position: i + 1, SongID: id}
+	}
+	if len(tracks) > 0 {
+		if err := db.Omit("Album", "Song").Create(&tracks).Error; err != nil {
+			return nil, err
+		}
+	}
+	return tracks, nil
+}
+
+// FindAlbum возвращает альбом с артистом и треками (вместе с песнями) по порядку.
+func FindAlbum(db *gorm.DB, id uint) (models.Album, error) {
+	var album models.Album
+	err := db.Preload("Artist").
+		Preload("Tracks", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		Preload("Tracks.Song").
+		Preload("Tracks.Song.Artist").
+		First(&album, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return album, ErrAlbumNotFound
+	}
+	return album, err
+}
+
+// DeleteAlbum удаляет альбом вместе с треклистом. Сами песни остаются.
+func DeleteAlbum(db *gorm.DB, id uint) error {
+	result := db.Delete(&models.Album{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlbumNotFound
+	}
+	return nil
+}
+
+func normalizeAlbumType(albumType string) (string, error) {
+	albumType = strings.ToLower(strings.TrimSpace(albumType))
+	switch albumType {
+	case "":
+		return AlbumTypeAlbum, nil
+	case AlbumTypeAlbum, AlbumTypeEP, AlbumTypeSingle:
+		return albumType, nil
+	}
+	return "", ErrInvalidAlbumType
+}
+
+func validateCoverURL(cover string) error {
+	if cover == "" {
+		return nil
+	}
+	u, err := url.Parse(cover)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidCover
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrArtistNotFound      = errors.New("Артист не найден")
+	ErrArtistAliasNotFound = errors.New("Псевдоним не найден")
+	ErrInvalidArtistAlias  = errors.New("Псевдоним не должен быть пустым и совпадать с именем артиста")
+	ErrArtistAliasExists   = errors.New("Имя уже занято другим артистом, объедините артистов через merge")
+	ErrInvalidArtistMerge  = errors.New("Нельзя объединить артиста с самим собой")
+)
+
+// FindArtistByName ищет артиста по имени или псевдониму без учёта регистра.
+// Если артиста нет, возвращает gorm.ErrRecordNotFound.
+func FindArtistByName(db *gorm.DB, name string) (models.Artist, error) {
+	var artist models.Artist
+	err := db.Where("LOWER(name) = LOWER(?)", name).First(&artist).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return artist, err
+	}
+	err = db.Where("id = (SELECT artist_id FROM artist_aliases WHERE LOWER(name) = LOWER(?) LIMIT 1)", name).
+		First(&artist).Error
+	return artist, err
+}
+
+// ResolveArtist ищет артиста по имени или псевдониму без учёта регистра и создаёт его, если такого ещё нет.
+// Имя сравнивается целиком, поэтому % и _ в названии группы не работают как шаблоны LIKE.
+// Создание идёт через INSERT ... ON CONFLICT (LOWER(name)) DO NOTHING по уникальному
+// индексу idx_artists_name_lower, поэтому параллельные запросы с новой группой, даже
+// в разном регистре, не падают на индексе, а получают уже созданного артиста.
+func ResolveArtist(db *gorm.DB, name string) (models.Artist, error) {
+	artist, err := FindArtistByName(db, name)
+	if err == nil {
+		logger.Log.Infof("Найден существующий артист: %v", artist)
+		return artist, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return artist, err
+	}
+
+	artist = models.Artist{Name: name}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "LOWER(name)", Raw: true}},
+		DoNothing: true,
+	}).Create(&artist)
+	if result.Error != nil {
+		return artist, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if err := db.Where("LOWER(name) = LOWER(?)", name).First(&artist).Error; err != nil {
+			return artist, err
+		}
+		logger.Log.Infof("Артист создан параллельным запросом: %v", artist)
+		return artist, nil
+	}
+	logger.Log.Infof("Создан новый артист: %v", artist)
+	return artist, PublishEvent(db, EventArtistCreated, artist.ID, artist)
+}
+
+// FindArtist возвращает артиста с псевдонимами.
+func FindArtist(db *gorm.DB, id uint) (models.Artist, error) {
+	var artist models.Artist
+	err := db.Preload("Aliases", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).First(&artist, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return artist, ErrArtistNotFound
+	}
+	return artist, err
+}
+
+// AddArtistAlias добавляет артисту псевдоним. Повторное добавление того же
+// псевдонима ничего не меняет; имя или псевдоним другого артиста занимать нельзя.
+func AddArtistAlias(db *gorm.DB, artistID uint, name string) (models.ArtistAlias, error) {
+	alias, created, err := addArtistAlias(db, artistID, name)
+	if err != nil || !created {
+		return alias, err
+	}
+	return alias, publishArtistUpdated(db, artistID)
+}
+
+// addArtistAlias добавляет псевдоним без публикации события; created — псевдонима ещё не было.
+func addArtistAlias(db *gorm.DB, artistID uint, name string) (models.ArtistAlias, bool, error) {
+	name = strings.TrimSpace(name)
+	alias := models.ArtistAlias{ArtistID: artistID, Name: name}
+	artist, err := FindArtist(db, artistID)
+	if err != nil {
+		return alias, false, err
+	}
+	if name == "" || strings.EqualFold(name, artist.Name) {
+		return alias, false, ErrInvalidArtistAlias
+	}
+
+	owner, err := FindArtistByName(db, name)
+	switch {
+	case err == nil && owner.ID == artistID:
+		err = db.Where("artist_id = ? AND LOWER(name) = LOWER(?)", artistID, name).First(&alias).Error
+		return alias, false, err
+	case err == nil:
+		return alias, false, ErrArtistAliasExists
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return alias, false, err
+	}
+
+	if err := db.Create(&alias).Error; err != nil {
+		return alias, false, err
+	}
+	logger.Log.Infof("Артисту %d добавлен псевдоним %q", artistID, name)
+	return alias, true, nil
+}
+
+// publishArtistUpdated публикует artist.updated с артистом и его псевдонимами.
+func publishArtistUpdated(db *gorm.DB, artistID uint) error {
+	artist, err := FindArtist(db, artistID)
+	if err != nil {
+		return err
+	}
+	return PublishEvent(db, EventArtistUpdated, artistID, artist)
+}
+
+// DeleteArtistAlias удаляет псевдоним артиста.
+func DeleteArtistAlias(db *gorm.DB, artistID, aliasID uint) error {
+	result := db.Where("id = ? AND artist_id = ?", aliasID, artistID).Delete(&models.ArtistAlias{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrArtistAliasNotFound
+	}
+	return publishArtistUpdated(db, artistID)
+}
+
+// RenameArtist меняет имя артиста. Имя или псевдоним другого артиста занимать
+// нельзя: таких артистов нужно объединить через merge. Собственный псевдоним,
+// совпадающий с новым именем, удаляется.
+func RenameArtist(db *gorm.DB, artistID uint, name string) (models.Artist, error) {
+	name = strings.TrimSpace(name)
+	var artist models.Artist
+	if err := db.First(&artist, artistID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return artist, ErrArtistNotFound
+		}
+		return artist, err
+	}
+	if artist.Name == name {
+		return artist, nil
+	}
+
+	owner, err := FindArtistByName(db, name)
+	switch {
+	case err == nil && owner.ID != artistID:
+		return artist, ErrArtistAliasExists
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		return artist, err
+	}
+	if err := db.Where("artist_id = ? AND LOWER(name) = LOWER(?)", artistID, name).Delete(&models.ArtistAlias{}).Error; err != nil {
+		return artist, err
+	}
+
+	logger.Log.Infof("Артист %d переименован: %q -> %q", artistID, artist.Name, name)
+	artist.Name = name
+	if err := db.Save(&artist).Error; err != nil {
+		return artist, err
+	}
+	return artist, publishArtistUpdated(db, artistID)
+}
+
+// ArtistSongIDs возвращает ID песен, в карточке которых есть артист: как
+// основной исполнитель или как участник.
+func ArtistSongIDs(db *gorm.DB, artistID uint) ([]uint, error) {
+	var songIDs []uint
+	err := db.Raw(`SELECT id FROM songs WHERE artist_id = @artist
+		UNION SELECT song_id FROM song_credits WHERE artist_id = @artist`,
+		map[string]interface{}{"artist": artistID}).Scan(&songIDs).Error
+	return songIDs, err
+}
+
+// MergeArtists переносит песни, участие в песнях, альбомы и псевдонимы дубликата
+// к основному артисту, сохраняет имя дубликата как псевдоним и удаляет дубликат.
+// Возвращает ID песен, у которых поменялись исполнители, чтобы сбросить их кеш.
+func MergeArtists(db *gorm.DB, artistID, duplicateID uint) (models.ArtistMergeResult, []uint, error) {
+	var result models.ArtistMergeResult
+	if artistID == duplicateID {
+		return result, nil, ErrInvalidArtistMerge
+	}
+
+	var artists []models.Artist
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", []uint{artistID, duplicateID}).Order("id").Find(&artists).Error; err != nil {
+		return result, nil, err
+	}
+	if len(artists) != 2 {
+		return result, nil, ErrArtistNotFound
+	}
+	duplicate := artists[0]
+	if duplicate.ID != duplicateID {
+		duplicate = artists[1]
+	}
+
+	songIDs, err := ArtistSongIDs(db, duplicateID)
+	if err != nil {
+		return result, nil, err
+	}
+
+	params := map[string]interface{}{"artist": artistID, "duplicate": duplicateID}
+	for _, statement := range []string{
+		"UPDATE songs SET artist_id = @artist, updated_at = NOW() WHERE artist_id = @duplicate",
+		// Если у песни уже есть такая же роль у основного артиста, запись дубликата просто удаляется.
+		`UPDATE song_credits SET artist_id = @artist WHERE artist_id = @duplicate AND NOT EXISTS (
+			SELECT 1 FROM song_credits existing WHERE existing.song_id = song_credits.song_id
+				AND existing.artist_id = @artist AND existing.role = song_credits.role)`,
+		"DELETE FROM song_credits WHERE artist_id = @duplicate",
+		"UPDATE albums SET artist_id = @artist, updated_at = NOW() WHERE artist_id = @duplicate",
+		"UPDATE artist_aliases SET artist_id = @artist WHERE artist_id = @duplicate",
+	} {
+		if err := db.Exec(statement, params).Error; err != nil {
+			return result, nil, err
+		}
+	}
+	if err := db.Delete(&models.Artist{}, duplicateID).Error; err != nil {
+		return result, nil, err
+	}
+	// Прежнее имя дубликата продолжает находить основного артиста. Отдельного
+	// artist.updated не публикуем: всё описывает событие artist.merged.
+	if _, _, err := addArtistAlias(db, artistID, duplicate.Name); err != nil && !errors.Is(err, ErrInvalidArtistAlias) {
+		return result, nil, err
+	}
+
+	artist, err := FindArtist(db, artistID)
+	if err != nil {
+		return result, nil, err
+	}
+	if err := PublishEvent(db, EventArtistMerged, artistID, artistMergedData{Artist: artist, DuplicateID: duplicateID, SongIDs: songIDs}); err != nil {
+		return result, nil, err
+	}
+	logger.Log.Infof("Артист %d объединён с %d, перенесено песен: %d", duplicateID, artistID, len(songIDs))
+	return models.ArtistMergeResult{Artist: artist, MovedSongs: len(songIDs)}, songIDs, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultChangesLimit = 100
+	MaxChangesLimit     = 1000
+	// Дольше минуты соединение могут оборвать прокси по таймауту простоя.
+	MaxChangesWait = 60 * time.Second
+	// Как часто long-poll проверяет появление новых событий.
+	changesPollInterval = 500 * time.Millisecond
+)
+
+// ListChanges возвращает до limit событий каталога с номером больше since по порядку.
+func ListChanges(db *gorm.DB, since uint64, limit int) (models.ChangesResponse, error) {
+	response := models.ChangesResponse{Events: []models.Event{}, NextSince: since}
+	err := db.Where("seq > ?", since).Order("seq").Limit(limit + 1).Find(&response.Events).Error
+	if err != nil {
+		return response, err
+	}
+	if len(response.Events) > limit {
+		response.Events = response.Events[:limit]
+		response.HasMore = true
+	}
+	if n := len(response.Events); n > 0 {
+		response.NextSince = response.Events[n-1].Seq
+	}
+	return response, nil
+}
+
+// WaitForChanges работает как ListChanges, но если новых событий нет, ждёт их
+// до wait или до отмены ctx и возвращает пустой ответ по истечении времени.
+func WaitForChanges(ctx context.Context, db *gorm.DB, since uint64, limit int, wait time.Duration) (models.ChangesResponse, error) {
+	deadline := time.Now().Add(wait)
+	ticker := time.NewTicker(changesPollInterval)
+	defer ticker.Stop()
+	for {
+		response, err := ListChanges(db.WithContext(ctx), since, limit)
+		if err != nil && ctx.Err() != nil {
+			// Клиент ушёл, не дождавшись ответа: отвечать уже некому.
+			return response, nil
+		}
+		if err != nil || len(response.Events) > 0 || !time.Now().Before(deadline) {
+			return response, err
+		}
+		select {
+		case <-ctx.Done():
+			return response, nil
+		case <-ticker.C:
+		}
+	}
+}
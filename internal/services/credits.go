@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	CreditRolePrimary  = "primary"
+	CreditRoleFeatured = "featured"
+	CreditRoleComposer = "composer"
+	CreditRoleLyricist = "lyricist"
+	CreditRoleProducer = "producer"
+)
+
+var (
+	ErrInvalidCreditRole = errors.New("Некорректная роль, допустимы primary, featured, composer, lyricist, producer")
+	ErrNoPrimaryCredit   = errors.New("Нужен хотя бы один основной исполнитель (роль primary) с непустым именем")
+
+	creditRoles = map[string]bool{
+		CreditRolePrimary:  true,
+		CreditRoleFeatured: true,
+		CreditRoleComposer: true,
+		CreditRoleLyricist: true,
+		CreditRoleProducer: true,
+	}
+
+	// "A feat. B", "A ft B", "A featuring B", "A (feat. B)". Перед меткой обязателен
+	// пробел, чтобы не резать названия вроде "Daft Punk".
+	featPattern = regexp.MustCompile(`(?i)\s+[(\[]?(?:feat\.?|ft\.?|featuring)\s+`)
+
+	// Разделители приглашённых исполнителей: "B, C & D", "B and C", "B и C", "B x C".
+	featSeparator = regexp.MustCompile(`(?i)\s*(?:,|&|\s+and\s+|\s+и\s+|\s+x\s+)\s*`)
+)
+
+// ParseArtistCredits разбирает строку группы вида "A feat. B, C & D" на основного
+// исполнителя и приглашённых. Сам основной исполнитель не делится: "Simon & Garfunkel"
+// остаётся одним артистом. Приглашённые делятся по разделителям, но если known
+// узнаёт имя из нескольких частей подряд ("Earth, Wind & Fire"), оно остаётся
+// целым; выбирается самое длинное узнанное имя. known может быть nil.
+func ParseArtistCredits(group string, known func(name string) bool) (primary string, featured []string) {
+	group = strings.TrimSpace(group)
+	loc := featPattern.FindStringIndex(group)
+	if loc == nil {
+		return group, nil
+	}
+
+	primary = strings.TrimSpace(group[:loc[0]])
+	rest := strings.TrimSpace(strings.TrimRight(group[loc[1]:], ")] "))
+	seen := map[string]bool{strings.ToLower(primary): true}
+	for _, name := range splitFeatured(rest, known) {
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		featured = append(featured, name)
+	}
+	if primary == "" {
+		return group, nil
+	}
+	return primary, featured
+}
+
+// splitFeatured делит список приглашённых по разделителям, склеивая соседние
+// части обратно, если known узнаёт их вместе с разделителями как одно имя.
+func splitFeatured(rest string, known func(name string) bool) []string {
+	// Границы частей: part i — rest[starts[i]:ends[i]].
+	seps := featSeparator.FindAllStringIndex(rest, -1)
+	starts := []int{0}
+	ends := make([]int, 0, len(seps)+1)
+	for _, sep := range seps {
+		ends = append(ends, sep[0])
+		starts = append(starts, sep[1])
+	}
+	ends = append(ends, len(rest))
+
+	names := make([]string, 0, len(starts))
+	for i := 0; i < len(starts); {
+		j := i
+		if known != nil {
+			for k := len(starts) - 1; k > i; k-- {
+				if known(strings.TrimSpace(rest[starts[i]:ends[k]])) {
+					j = k
+					break
+				}
+			}
+		}
+		names = append(names, strings.TrimSpace(rest[starts[i]:ends[j]]))
+		i = j + 1
+	}
+	return names
+}
+
+// ResolveGroupCredits разбирает строку группы и находит (или создаёт) артистов.
+// Первым в списке всегда идёт основной исполнитель.
+func ResolveGroupCredits(db *gorm.DB, group string) ([]models.SongCredit, error) {
+	primary, featured := ParseArtistCredits(group, func(name string) bool {
+		_, err := FindArtistByName(db, name)
+		return err == nil
+	})
+	if primary == "" {
+		return nil, ErrInvalidInput
+	}
+
+	names := append([]string{primary}, featured...)
+	credits := make([]models.SongCredit, 0, len(names))
+	for i, name := range names {
+		artist, err := ResolveArtist(db, name)
+		if err != nil {
+			return nil, err
+		}
+		role := CreditRoleFeatured
+		if i == 0 {
+			role = CreditRolePrimary
+		}
+		credits = append(credits, models.SongCredit{ArtistID: artist.ID, Artist: artist, Role: role, Position: i})
+	}
+	return credits, nil
+}
+
+// SaveGroupCredits заменяет основного и приглашённых исполнителей песни.
+// Композиторы, авторы текста и продюсеры не затрагиваются.
+func SaveGroupCredits(db *gorm.DB, songID uint, credits []models.SongCredit) error {
+	err := db.Where("song_id = ? AND role IN ?", songID, []string{CreditRolePrimary, CreditRoleFeatured}).
+		Delete(&models.SongCredit{}).Error
+	if err != nil {
+		return err
+	}
+	return insertCredits(db, songID, credits)
+}
+
+// ReplaceSongCredits полностью заменяет участников песни. Порядок во входном списке
+// задаёт позицию; первый основной исполнитель становится артистом песни.
+func ReplaceSongCredits(db *gorm.DB, songID uint, inputs []models.SongCreditInput) ([]models.SongCredit, error) {
+	var song models.Song
+	if err := db.First(&song, songID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSongNotFound
+		}
+		return nil, err
+	}
+
+	hasPrimary := false
+	for i := range inputs {
+		inputs[i].Role = strings.ToLower(strings.TrimSpace(inputs[i].Role))
+		if !creditRoles[inputs[i].Role] {
+			return nil, ErrInvalidCreditRole
+		}
+		if strings.TrimSpace(inputs[i].GroupName) == "" {
+			return nil, ErrNoPrimaryCredit
+		}
+		if inputs[i].Role == CreditRolePrimary {
+			hasPrimary = true
+		}
+	}
+	if !hasPrimary {
+		return nil, ErrNoPrimaryCredit
+	}
+
+	credits := make([]models.SongCredit, 0, len(inputs))
+	for i, input := range inputs {
+		artist, err := ResolveArtist(db, strings.TrimSpace(input.GroupName))
+		if err != nil {
+			return nil, err
+		}
+		credits = append(credits, models.SongCredit{ArtistID: artist.ID, Artist: artist, Role: input.Role, Position: i})
+		if input.Role == CreditRolePrimary && song.ArtistID != artist.ID && !primaryAssigned(credits[:i]) {
+			song.ArtistID = artist.ID
+			if err := db.Model(&song).Update("artist_id", artist.ID).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := db.Where("song_id = ?", songID).Delete(&models.SongCredit{}).Error; err != nil {
+		return nil, err
+	}
+	if err := insertCredits(db, songID, credits); err != nil {
+		return nil, err
+	}
+	if err := PublishSongEvent(db, EventSongUpdated, songID); err != nil {
+		return nil, err
+	}
+	return FindSongCredits(db, songID)
+}
+
+// FindSongCredits возвращает участников песни по порядку вместе с артистами.
+func FindSongCredits(db *gorm.DB, songID uint) ([]models.SongCredit, error) {
+	credits := []models.SongCredit{}
+	err := db.Preload("Artist").Where("song_id = ?", songID).Order("position, id").Find(&credits).Error
+	return credits, err
+}
+
+// BackfillSongCredits создаёт запись основного исполнителя для песен, у которых
+// ещё нет участников.
+func BackfillSongCredits(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO song_credits (song_id, artist_id, role, position, created_at)
+		SELECT songs.id, songs.artist_id, ?, 0, NOW() FROM songs
+		WHERE songs.artist_id IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM song_credits WHERE song_credits.song_id = songs.id)`, CreditRolePrimary).Error
+}
+
+func insertCredits(db *gorm.DB, songID uint, credits []models.SongCredit) error {
+	if len(credits) == 0 {
+		return nil
+	}
+	for i := range credits {
+		credits[i].SongID = songID
+	}
+	// Один и тот же артист в одной роли учитывается один раз.
+	return db.Omit("Song", "Artist").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "song_id"}, {Name: "artist_id"}, {Name: "role"}},
+		DoNothing: true,
+	}).Create(&credits).Error
+}
+
+func primaryAssigned(credits []models.SongCredit) bool {
+	for _, credit := range credits {
+		if credit.Role == CreditRolePrimary {
+			return true
+		}
+	}
+	return false
+}
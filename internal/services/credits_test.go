@@ -0,0 +1,41 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseArtistCredits(t *testing.T) {
+	artists := map[string]bool{"earth, wind & fire": true, "crosby, stills & nash": true, "simon & garfunkel": true}
+	known := func(name string) bool { return artists[strings.ToLower(name)] }
+
+	tests := []struct {
+		group        string
+		known        func(string) bool
+		wantPrimary  string
+		wantFeatured []string
+	}{
+		{group: "Muse", wantPrimary: "Muse"},
+		{group: "Simon & Garfunkel", wantPrimary: "Simon & Garfunkel"},
+		{group: "Daft Punk", wantPrimary: "Daft Punk"},
+		{group: "Rihanna feat. Drake", wantPrimary: "Rihanna", wantFeatured: []string{"Drake"}},
+		{group: "A ft B, C & D", wantPrimary: "A", wantFeatured: []string{"B", "C", "D"}},
+		{group: "A (feat. B and C)", wantPrimary: "A", wantFeatured: []string{"B", "C"}},
+		{group: "A featuring B x C", wantPrimary: "A", wantFeatured: []string{"B", "C"}},
+		{group: "A feat. B и C", wantPrimary: "A", wantFeatured: []string{"B", "C"}},
+		{group: "A feat. a, B, b", wantPrimary: "A", wantFeatured: []string{"B"}},
+		{group: "A feat. Earth, Wind & Fire", wantPrimary: "A", wantFeatured: []string{"Earth", "Wind", "Fire"}},
+		{group: "A feat. Earth, Wind & Fire", known: known, wantPrimary: "A", wantFeatured: []string{"Earth, Wind & Fire"}},
+		{group: "A feat. B, Earth, Wind & Fire & C", known: known, wantPrimary: "A", wantFeatured: []string{"B", "Earth, Wind & Fire", "C"}},
+		{group: "A feat. Simon & Garfunkel, Crosby, Stills & Nash", known: known, wantPrimary: "A", wantFeatured: []string{"Simon & Garfunkel", "Crosby, Stills & Nash"}},
+		{group: "A feat. B & C", known: known, wantPrimary: "A", wantFeatured: []string{"B", "C"}},
+		{group: "feat. B", wantPrimary: "feat. B"},
+	}
+	for _, tt := range tests {
+		primary, featured := ParseArtistCredits(tt.group, tt.known)
+		if primary != tt.wantPrimary || !reflect.DeepEqual(featured, tt.wantFeatured) {
+			t.Errorf("ParseArtistCredits(%q) = %q, %q, want %q, %q", tt.group, primary, featured, tt.wantPrimary, tt.wantFeatured)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Типы событий каталога.
+const (
+	EventSongCreated   = "song.created"
+	EventSongUpdated   = "song.updated"
+	EventSongDeleted   = "song.deleted"
+	EventArtistCreated = "artist.created"
+	EventArtistUpdated = "artist.updated"
+	EventArtistMerged  = "artist.merged"
+)
+
+var EventTypes = []string{
+	EventSongCreated, EventSongUpdated, EventSongDeleted,
+	EventArtistCreated, EventArtistUpdated, EventArtistMerged,
+}
+
+// artistMergedData — данные события artist.merged.
+type artistMergedData struct {
+	Artist      models.Artist `json:"artist"`
+	DuplicateID uint          `json:"duplicateId"`
+	SongIDs     []uint        `json:"songIds"`
+}
+
+// Ключ транзакционной advisory-блокировки, которой упорядочиваются публикации.
+const eventsLockKey = 4045
+
+// PublishEvent публикует событие каталога: записывает его в outbox catalog_events
+// и ставит в очередь доставки вебхуков. Вызывается в той же транзакции, что и само
+// изменение: если транзакция откатится, события не будет.
+//
+// Перед вставкой берётся блокировка до конца транзакции, поэтому транзакции с
+// событиями фиксируются по очереди и события становятся видны строго в порядке Seq:
+// читатель ленты изменений не пропустит событие с меньшим номером, зафиксированное позже.
+func PublishEvent(db *gorm.DB, eventType string, entityID uint, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := db.Exec("SELECT pg_advisory_xact_lock(?)", eventsLockKey).Error; err != nil {
+		return err
+	}
+	entityType, _, _ := strings.Cut(eventType, ".")
+	event := models.Event{
+		ID:         newJobID(),
+		Type:       eventType,
+		EntityType: entityType,
+		EntityID:   entityID,
+		OccurredAt: time.Now().UTC(),
+		Data:       payload,
+	}
+	if err := db.Create(&event).Error; err != nil {
+		return err
+	}
+	return enqueueWebhookDeliveries(db, event)
+}
+
+// PublishSongEvent публикует событие с полной карточкой песни: артистом,
+// участниками, ссылками, жанрами и тегами.
+func PublishSongEvent(db *gorm.DB, eventType string, songID uint) error {
+	song, err := findSongSnapshot(db, songID)
+	if err != nil {
+		return err
+	}
+	return PublishEvent(db, eventType, songID, song)
+}
+
+func findSongSnapshot(db *gorm.DB, songID uint) (models.Song, error) {
+	var song models.Song
+	err := db.Preload("Artist").Preload("Links").
+		Preload("Credits", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Preload("Credits.Artist").Preload("Genres").Preload("Tags").
+		First(&song, songID).Error
+	return song, err
+}
+
+// eventMatches сообщает, подходит ли событие под список типов подписки.
+// Пустой список и «*» — все события, «song.*» — все события песен.
+func eventMatches(patterns []string, eventType string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == eventType ||
+			(strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// validEventPattern проверяет тип события или маску из подписки.
+func validEventPattern(pattern string) bool {
+	for _, eventType := range EventTypes {
+		if eventMatches([]string{pattern}, eventType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+
+	ImportStatusRunning = "running"
+	ImportStatusDone    = "done"
+
+	ImportActionCreated = "created"
+	ImportActionUpdated = "updated"
+
+	// Сколько хранить завершённые задачи импорта в памяти.
+	importJobRetention = 24 * time.Hour
+	// Максимальная длина строки JSONL (тексты песен бывают длинными).
+	maxJSONLLineSize = 1 << 20
+)
+
+// ImportRow — одна строка файла импорта.
+type ImportRow struct {
+	Line        int    `json:"-"`
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+
+	parseErr error
+}
+
+type ImportOptions struct {
+	Format string
+	DryRun bool
+	Enrich bool
+}
+
+var (
+	importJobsMu sync.RWMutex
+	importJobs   = make(map[string]*models.ImportJob)
+)
+
+// DetectImportFormat определяет формат файла импорта по явно указанному формату,
+// имени файла или Content-Type.
+func DetectImportFormat(format, filename, contentType string) (string, error) {
+	switch strings.ToLower(format) {
+	case ImportFormatCSV:
+		return ImportFormatCSV, nil
+	case ImportFormatJSONL, "ndjson":
+		return ImportFormatJSONL, nil
+	case "":
+	default:
+		return "", fmt.Errorf("неизвестный формат импорта: %s", format)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return ImportFormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return ImportFormatJSONL, nil
+	}
+
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return ImportFormatCSV, nil
+	case strings.Contains(contentType, "ndjson"), strings.Contains(contentType, "jsonl"):
+		return ImportFormatJSONL, nil
+	}
+	return "", errors.New("не удалось определить формат импорта, укажите format=csv или format=jsonl")
+}
+
+// ParseImport читает строки импорта из CSV (с заголовком) или JSON Lines.
+// Ошибки разбора отдельных строк не прерывают чтение и попадают в результат импорта.
+func ParseImport(r io.Reader, format string) ([]ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseImportCSV(r)
+	case ImportFormatJSONL:
+		return parseImportJSONL(r)
+	}
+	return nil, fmt.Errorf("неизвестный формат импорта: %s", format)
+}
+
+func parseImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["group"]; !ok {
+		return nil, errors.New("в заголовке CSV нет колонки group")
+	}
+	if _, ok := columns["song"]; !ok {
+		return nil, errors.New("в заголовке CSV нет колонки song")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// При ошибке разбора FieldPos недоступен: номер строки берём из ошибки.
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, ImportRow{Line: parseErr.StartLine, parseErr: err})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, ImportRow{
+			Line:        line,
+			Group:       field(record, "group"),
+			Song:        field(record, "song"),
+			ReleaseDate: field(record, "releasedate"),
+			Text:        field(record, "text"),
+			Link:        field(record, "link"),
+		})
+	}
+	return rows, nil
+}
+
+func parseImportJSONL(r io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJSONLLineSize)
+
+	var rows []ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		row := ImportRow{Line: line}
+		if err := json.Unmarshal([]byte(raw), &row); err != nil {
+			row.parseErr = fmt.Errorf("некорректный JSON: %v", err)
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения JSONL: %v", err)
+	}
+	return rows, nil
+}
+
+// StartImport запускает импорт в фоне и возвращает созданную задачу.
+// Прогресс можно получить через GetImportJob.
+func StartImport(db *gorm.DB, rows []ImportRow, opts ImportOptions) models.ImportJob {
+	job := newImportJob(rows, opts)
+
+	importJobsMu.Lock()
+	pruneImportJobs()
+	importJobs[job.ID] = job
+	snapshot := snapshotImportJob(job)
+	importJobsMu.Unlock()
+
+	go processImport(db, job, rows, opts)
+	return snapshot
+}
+
+// RunImport выполняет импорт синхронно, например из CLI.
+func RunImport(db *gorm.DB, rows []ImportRow, opts ImportOptions) models.ImportJob {
+	job := newImportJob(rows, opts)
+	processImport(db, job, rows, opts)
+
+	importJobsMu.RLock()
+	defer importJobsMu.RUnlock()
+	return snapshotImportJob(job)
+}
+
+// GetImportJob возвращает текущее состояние задачи импорта.
+func GetImportJob(id string) (models.ImportJob, bool) {
+	importJobsMu.RLock()
+	defer importJobsMu.RUnlock()
+
+	job, ok := importJobs[id]
+	if !ok {
+		return models.ImportJob{}, false
+	}
+	return snapshotImportJob(job), true
+}
+
+func newImportJob(rows []ImportRow, opts ImportOptions) *models.ImportJob {
+	return &models.ImportJob{
+		ID:        newJobID(),
+		Status:    ImportStatusRunning,
+		Format:    opts.Format,
+		DryRun:    opts.DryRun,
+		Enrich:    opts.Enrich,
+		Total:     len(rows),
+		Errors:    []models.ImportRowResult{},
+		StartedAt: time.Now(),
+	}
+}
+
+func processImport(db *gorm.DB, job *models.ImportJob, rows []ImportRow, opts ImportOptions) {
+	logger.Log.Infof("Старт импорта %s: строк %d, dryRun=%t, enrich=%t", job.ID, len(rows), opts.DryRun, opts.Enrich)
+	// Песни, уже учтённые пробным запуском: повтор той же песни
+	// в файле при настоящем импорте обновляет её, а не создаёт вторую.
+	planned := make(map[string]bool)
+	for _, row := range rows {
+		result := importRow(db, row, opts, planned)
+
+		importJobsMu.Lock()
+		job.Processed++
+		switch {
+		case result.Error != "":
+			job.Failed++
+		case result.Action == ImportActionCreated:
+			job.Created++
+		case result.Action == ImportActionUpdated:
+			job.Updated++
+		}
+		if result.Error != "" || result.Warning != "" {
+			job.Errors = append(job.Errors, result)
+		}
+		importJobsMu.Unlock()
+	}
+
+	importJobsMu.Lock()
+	finishedAt := time.Now()
+	job.Status = ImportStatusDone
+	job.FinishedAt = &finishedAt
+	importJobsMu.Unlock()
+	logger.Log.Infof("Импорт %s завершён: создано %d, обновлено %d, ошибок %d", job.ID, job.Created, job.Updated, job.Failed)
+
+	if !opts.DryRun && job.Created+job.Updated > 0 {
+		if err := RebuildSuggestions(db); err != nil {
+			logger.Log.Errorf("Ошибка построения индекса автодополнения: %v", err)
+		}
+	}
+}
+
+func importRow(db *gorm.DB, row ImportRow, opts ImportOptions, planned map[string]bool) models.ImportRowResult {
+	row.Group = strings.TrimSpace(row.Group)
+	row.Song = strings.TrimSpace(row.Song)
+	row.ReleaseDate = strings.TrimSpace(row.ReleaseDate)
+	row.Link = strings.TrimSpace(row.Link)
+
+	result := models.ImportRowResult{Line: row.Line, Group: row.Group, Song: row.Song}
+	fail := func(err error) models.ImportRowResult {
+		logger.Log.Errorf("Строка импорта %d: %v", row.Line, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	if err := validateImportRow(row); err != nil {
+		return fail(err)
+	}
+
+	var existing models.Song
+	found := false
+	primary, _ := ParseArtistCredits(row.Group, nil)
+	// Ключ песни в задаче: артист из БД по id, новый — по имени без учёта регистра.
+	key := "name:" + strings.ToLower(primary) + "\x00" + strings.ToLower(row.Song)
+	artist, err := FindArtistByName(db, primary)
+	switch {
+	case err == nil:
+		key = "id:" + strconv.FormatUint(uint64(artist.ID), 10) + "\x00" + strings.ToLower(row.Song)
+		err = db.Where("artist_id = ? AND LOWER(song) = LOWER(?)", artist.ID, row.Song).First(&existing).Error
+		if err == nil {
+			found = true
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fail(err)
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return fail(err)
+	}
+
+	result.Action = ImportActionCreated
+	if found {
+		result.Action = ImportActionUpdated
+		result.SongID = existing.ID
+	}
+	if opts.DryRun {
+		if !found && planned[key] {
+			result.Action = ImportActionUpdated
+		}
+		planned[key] = true
+		return result
+	}
+
+	if opts.Enrich && needsEnrichment(row, existing) {
+		if detail, err := FetchSongDetail(row.Group, row.Song); err != nil {
+			result.Warning = fmt.Sprintf("не удалось обогатить данные: %v", err)
+		} else {
+			if row.ReleaseDate == "" && existing.ReleaseDate == nil {
+				row.ReleaseDate = detail.ReleaseDate
+			}
+			if row.Text == "" && existing.Text == "" {
+				row.Text = detail.Text
+			}
+			if row.Link == "" && existing.Link == "" {
+				row.Link = detail.Link
+			}
+		}
+	}
+
+	song := existing
+	if row.ReleaseDate != "" {
+		parsedDate, err := ParseReleaseDate(row.ReleaseDate)
+		if err != nil {
+			return fail(err)
+		}
+		song.ReleaseDate = parsedDate
+	}
+	if row.Text != "" {
+		song.Text = row.Text
+	}
+	if row.Link != "" {
+		song.Link = row.Link
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var credits []models.SongCredit
+		if !found {
+			var err error
+			credits, err = ResolveGroupCredits(tx, row.Group)
+			if err != nil {
+				return err
+			}
+			song.ArtistID = credits[0].ArtistID
+			song.Song = row.Song
+		}
+		if err := tx.Omit("Artist", "Links", "Credits", "Genres", "Tags").Save(&song).Error; err != nil {
+			return err
+		}
+		if !found {
+			if err := SaveGroupCredits(tx, song.ID, credits); err != nil {
+				return err
+			}
+		}
+		if row.Link != "" {
+			if _, err := AddSongLinks(tx, song.ID, row.Link); err != nil {
+				return err
+			}
+		}
+		if found {
+			return PublishSongEvent(tx, EventSongUpdated, song.ID)
+		}
+		return PublishSongEvent(tx, EventSongCreated, song.ID)
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if found {
+		cache.Del("song:" + strconv.FormatUint(uint64(song.ID), 10))
+	}
+	result.SongID = song.ID
+	return result
+}
+
+func validateImportRow(row ImportRow) error {
+	if row.parseErr != nil {
+		return row.parseErr
+	}
+	if row.Group == "" || row.Song == "" {
+		return errors.New("поля group и song обязательны")
+	}
+	if row.ReleaseDate != "" {
+		if _, err := ParseReleaseDate(row.ReleaseDate); err != nil {
+			return err
+		}
+	}
+	if row.Link != "" {
+		u, err := url.Parse(row.Link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("некорректная ссылка: %s", row.Link)
+		}
+	}
+	return nil
+}
+
+func needsEnrichment(row ImportRow, existing models.Song) bool {
+	return (row.ReleaseDate == "" && existing.ReleaseDate == nil) ||
+		(row.Text == "" && existing.Text == "") ||
+		(row.Link == "" && existing.Link == "")
+}
+
+// ParseReleaseDate разбирает дату релиза с точностью до года, месяца или дня
+// (форматы см. в models.ParsePartialDate). Пустая строка — неизвестная дата, nil.
+func ParseReleaseDate(value string) (*models.PartialDate, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	date, err := models.ParsePartialDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func snapshotImportJob(job *models.ImportJob) models.ImportJob {
+	snapshot := *job
+	snapshot.Errors = append([]models.ImportRowResult(nil), job.Errors...)
+	return snapshot
+}
+
+func pruneImportJobs() {
+	for id, job := range importJobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > importJobRetention {
+			delete(importJobs, id)
+		}
+	}
+}
+
+func newJobID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PlatformYouTube      = "youtube"
+	PlatformYouTubeMusic = "youtube_music"
+	PlatformSpotify      = "spotify"
+	PlatformAppleMusic   = "apple_music"
+	PlatformYandexMusic  = "yandex_music"
+	PlatformVK           = "vk"
+	PlatformSoundCloud   = "soundcloud"
+	PlatformDeezer       = "deezer"
+	PlatformTidal        = "tidal"
+	PlatformBandcamp     = "bandcamp"
+	PlatformAmazonMusic  = "amazon_music"
+	PlatformOther        = "other"
+
+	LinkKindVideo         = "video"
+	LinkKindOfficialVideo = "official_video"
+	LinkKindStreaming     = "streaming"
+	LinkKindPurchase      = "purchase"
+	LinkKindOther         = "other"
+)
+
+var (
+	ErrInvalidLink  = errors.New("Некорректная ссылка, ожидается http(s) URL")
+	ErrInvalidKind  = errors.New("Некорректный тип ссылки, допустимы video, official_video, streaming, purchase, other")
+	ErrLinkNotFound = errors.New("Ссылка не найдена")
+
+	// Платформы по домену (без www. и m.). Поддомены тоже учитываются.
+	platformHosts = map[string]string{
+		"youtube.com":       PlatformYouTube,
+		"youtu.be":          PlatformYouTube,
+		"music.youtube.com": PlatformYouTubeMusic,
+		"open.spotify.com":  PlatformSpotify,
+		"spotify.com":       PlatformSpotify,
+		"music.apple.com":   PlatformAppleMusic,
+		"itunes.apple.com":  PlatformAppleMusic,
+		"music.yandex.ru":   PlatformYandexMusic,
+		"music.yandex.com":  PlatformYandexMusic,
+		"vk.com":            PlatformVK,
+		"vk.ru":             PlatformVK,
+		"soundcloud.com":    PlatformSoundCloud,
+		"deezer.com":        PlatformDeezer,
+		"tidal.com":         PlatformTidal,
+		"listen.tidal.com":  PlatformTidal,
+		"bandcamp.com":      PlatformBandcamp,
+		"music.amazon.com":  PlatformAmazonMusic,
+	}
+
+	// Тип ссылки по умолчанию для платформы.
+	platformKinds = map[string]string{
+		PlatformYouTube:  LinkKindVideo,
+		PlatformBandcamp: LinkKindPurchase,
+		PlatformOther:    LinkKindOther,
+	}
+
+	linkKinds = map[string]bool{
+		LinkKindVideo:         true,
+		LinkKindOfficialVideo: true,
+		LinkKindStreaming:     true,
+		LinkKindPurchase:      true,
+		LinkKindOther:         true,
+	}
+
+	// Порты по умолчанию: в канонической ссылке не указываются.
+	defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+	// Параметры, которые не влияют на содержимое страницы и только мешают дедупликации.
+	trackingParams = map[string]bool{
+		"fbclid": true, "gclid": true, "yclid": true, "igshid": true, "si": true,
+		"feature": true, "ref": true, "ref_src": true, "_ga": true, "mc_cid": true,
+		"mc_eid": true, "from": true, "utm": true, "pp": true,
+	}
+)
+
+// CanonicalizeLink приводит ссылку к каноническому виду: домен в нижнем регистре
+// без www./m., без меток отслеживания (utm_*, si, fbclid и т.п.) и якоря. Ссылки
+// известных платформ переводятся на https, остальные сохраняют схему; порт
+// сохраняется, если он не стандартный для схемы. Ссылки YouTube (youtu.be,
+// shorts, embed) приводятся к youtube.com/watch?v=ID.
+func CanonicalizeLink(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return "", ErrInvalidLink
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", ErrInvalidLink
+	}
+	port := u.Port()
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	switch host {
+	case "youtu.be":
+		if id := strings.Trim(path, "/"); id != "" {
+			host, path, query = "youtube.com", "/watch", url.Values{"v": {id}}
+		}
+	case "youtube.com", "music.youtube.com":
+		for _, prefix := range []string{"/shorts/", "/embed/", "/live/", "/v/"} {
+			if id, ok := strings.CutPrefix(path, prefix); ok && id != "" {
+				path, query = "/watch", url.Values{"v": {id}}
+			}
+		}
+		if path == "/watch" && query.Get("v") != "" {
+			query = url.Values{"v": {query.Get("v")}}
+		}
+	}
+	if hostPlatform(host) != PlatformOther {
+		scheme = "https"
+	}
+	if host == "youtube.com" {
+		host = "www.youtube.com"
+	}
+	switch {
+	case port != "":
+		host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		host = "[" + host + "]"
+	}
+
+	canonical := url.URL{Scheme: scheme, Host: host, Path: path, RawQuery: query.Encode()}
+	return canonical.String(), nil
+}
+
+// DetectPlatform определяет платформу по домену ссылки.
+func DetectPlatform(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return PlatformOther
+	}
+	return hostPlatform(strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."), "m."))
+}
+
+// hostPlatform определяет платформу по домену без www./m. с учётом поддоменов.
+func hostPlatform(host string) string {
+	for host != "" {
+		if platform, ok := platformHosts[host]; ok {
+			return platform
+		}
+		_, rest, found := strings.Cut(host, ".")
+		if !found || !strings.Contains(rest, ".") {
+			break
+		}
+		host = rest
+	}
+	return PlatformOther
+}
+
+// NewSongLink строит ссылку песни: канонизирует URL, определяет платформу и тип.
+func NewSongLink(songID uint, raw, kind string) (models.SongLink, error) {
+	canonical, err := CanonicalizeLink(raw)
+	if err != nil {
+		return models.SongLink{}, err
+	}
+	platform := DetectPlatform(canonical)
+	if kind == "" {
+		kind = platformKinds[platform]
+		if kind == "" {
+			kind = LinkKindStreaming
+		}
+	}
+	if !linkKinds[kind] {
+		return models.SongLink{}, ErrInvalidKind
+	}
+	return models.SongLink{SongID: songID, Platform: platform, Kind: kind, URL: canonical}, nil
+}
+
+// AddSongLinks сохраняет ссылки песни, пропуская некорректные и уже существующие
+// (после канонизации). Возвращает все ссылки песни.
+func AddSongLinks(db *gorm.DB, songID uint, rawLinks ...string) ([]models.SongLink, error) {
+	seen := make(map[string]bool)
+	var links []models.SongLink
+	for _, raw := range rawLinks {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		link, err := NewSongLink(songID, raw, "")
+		if err != nil {
+			logger.Log.Errorf("Ссылка %q пропущена: %v", raw, err)
+			continue
+		}
+		if seen[link.URL] {
+			continue
+		}
+		seen[link.URL] = true
+		links = append(links, link)
+	}
+
+	if len(links) > 0 {
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "song_id"}, {Name: "url"}},
+			DoNothing: true,
+		}).Create(&links).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var all []models.SongLink
+	if err := db.Where("song_id = ?", songID).Order("id").Find(&all).Error; err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// BackfillSongLinks переносит ссылки из поля songs.link в таблицу ссылок
+// для песен, у которых их ещё нет.
+func BackfillSongLinks(db *gorm.DB) error {
+	var songs []models.Song
+	err := db.Select("id", "link").
+		Where("link <> '' AND NOT EXISTS (SELECT 1 FROM song_links WHERE song_links.song_id = songs.id)").
+		FindInBatches(&songs, 500, func(tx *gorm.DB, batch int) error {
+			for _, song := range songs {
+				if _, err := AddSongLinks(db, song.ID, song.Link); err != nil {
+					return fmt.Errorf("песня %d: %v", song.ID, err)
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+	logger.Log.Info("Ссылки песен перенесены в таблицу song_links")
+	return nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import "testing"
+
+func TestCanonicalizeLink(t *testing.T) {
+	tests := []struct {
+		raw, want string
+		wantErr   bool
+	}{
+		{raw: "https://youtu.be/dQw4w9WgXcQ?si=abc", want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{raw: "http://m.youtube.com/watch?v=dQw4w9WgXcQ&feature=share&t=10", want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{raw: "https://www.youtube.com/shorts/abc123/", want: "https://www.youtube.com/watch?v=abc123"},
+		{raw: "http://open.spotify.com/track/42?utm_source=x#top", want: "https://open.spotify.com/track/42"},
+		{raw: "https://artist.bandcamp.com/track/song", want: "https://artist.bandcamp.com/track/song"},
+		{raw: "https://YouTube.com:443/watch?v=abc", want: "https://www.youtube.com/watch?v=abc"},
+		{raw: "http://example.com/song", want: "http://example.com/song"},
+		{raw: "http://example.com:80/song", want: "http://example.com/song"},
+		{raw: "http://example.com:8080/song?utm_medium=x&id=1", want: "http://example.com:8080/song?id=1"},
+		{raw: "https://WWW.Example.com:8443/", want: "https://example.com:8443"},
+		{raw: "https://example.com:443/song", want: "https://example.com/song"},
+		{raw: "http://[::1]:8080/song", want: "http://[::1]:8080/song"},
+		{raw: "http://[::1]/song", want: "http://[::1]/song"},
+		{raw: "ftp://example.com/song", wantErr: true},
+		{raw: "example.com/song", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := CanonicalizeLink(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CanonicalizeLink(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CanonicalizeLink(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"songs/internal/models"
+)
+
+var (
+	lrcTimeTag = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcMetaTag = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+	lrcWordTag = regexp.MustCompile(`<(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+)
+
+// ParseLRC разбирает синхронизированный текст в формате LRC, включая расширенный
+// формат с пословными метками <mm:ss.xx>. Поддерживаются несколько меток времени
+// на строке и тег [offset:], метаданные ([ar:], [ti:] и т.п.) пропускаются.
+// Возвращает строки, отсортированные по времени, и признак пословной разметки.
+func ParseLRC(text string) ([]models.SyncedLine, bool, error) {
+	var (
+		lines    []models.SyncedLine
+		offset   int64
+		enhanced bool
+	)
+
+	text = strings.TrimPrefix(strings.ReplaceAll(text, "\r\n", "\n"), "\ufeff")
+	for n, raw := range strings.Split(text, "\n") {
+		lineNo := n + 1
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		if m := lrcMetaTag.FindStringSubmatch(line); m != nil && !lrcTimeTag.MatchString(line) {
+			if strings.EqualFold(m[1], "offset") {
+				value, err := strconv.ParseInt(strings.TrimSpace(m[2]), 10, 64)
+				if err != nil {
+					return nil, false, fmt.Errorf("строка %d: некорректный offset: %s", lineNo, m[2])
+				}
+				offset = value
+			}
+			continue
+		}
+
+		var times []int64
+		for {
+			m := lrcTimeTag.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			ms, err := lrcMillis(m[1], m[2], m[3])
+			if err != nil {
+				return nil, false, fmt.Errorf("строка %d: %v", lineNo, err)
+			}
+			times = append(times, ms)
+			line = line[len(m[0]):]
+		}
+		if len(times) == 0 {
+			return nil, false, fmt.Errorf("строка %d: нет корректной временной метки [mm:ss.xx]", lineNo)
+		}
+
+		lyric, words, err := parseLRCWords(strings.TrimSpace(line))
+		if err != nil {
+			return nil, false, fmt.Errorf("строка %d: %v", lineNo, err)
+		}
+		if len(words) > 0 {
+			enhanced = true
+		}
+
+		if len(words) > 0 {
+			for i := range words {
+				if words[i].Time < 0 {
+					words[i].Time = times[0]
+				}
+			}
+			if words[0].Time < times[0] {
+				return nil, false, fmt.Errorf("строка %d: метка слова раньше метки строки", lineNo)
+			}
+		}
+
+		// Строка с несколькими метками повторяется; пословные метки сдвигаются вместе с ней.
+		for _, t := range times {
+			synced := models.SyncedLine{Time: t, Text: lyric}
+			if len(words) > 0 {
+				synced.Words = make([]models.SyncedWord, len(words))
+				for i, w := range words {
+					synced.Words[i] = models.SyncedWord{Time: w.Time - times[0] + t, Text: w.Text}
+				}
+			}
+			lines = append(lines, synced)
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, false, fmt.Errorf("в тексте нет ни одной строки с временной меткой")
+	}
+
+	// Положительный offset означает, что текст должен показываться раньше.
+	for i := range lines {
+		lines[i].Time = max(lines[i].Time-offset, 0)
+		for j := range lines[i].Words {
+			lines[i].Words[j].Time = max(lines[i].Words[j].Time-offset, 0)
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time < lines[j].Time })
+	return lines, enhanced, nil
+}
+
+// parseLRCWords выделяет из текста строки пословные метки расширенного LRC.
+// Слова до первой метки получают время -1: его заменяет время строки.
+func parseLRCWords(line string) (string, []models.SyncedWord, error) {
+	tags := lrcWordTag.FindAllStringSubmatchIndex(line, -1)
+	if len(tags) == 0 {
+		return line, nil, nil
+	}
+
+	var words []models.SyncedWord
+	add := func(t int64, segment string) {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			words = append(words, models.SyncedWord{Time: t, Text: segment})
+		}
+	}
+
+	add(-1, line[:tags[0][0]])
+	last := int64(-1)
+	for i, tag := range tags {
+		t, err := lrcMillis(line[tag[2]:tag[3]], line[tag[4]:tag[5]], submatch(line, tag, 6))
+		if err != nil {
+			return "", nil, err
+		}
+		if t < last {
+			return "", nil, fmt.Errorf("пословные метки идут не по возрастанию")
+		}
+		last = t
+
+		end := len(line)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		add(t, line[tag[1]:end])
+	}
+
+	text := make([]string, len(words))
+	for i, w := range words {
+		text[i] = w.Text
+	}
+	return strings.Join(text, " "), words, nil
+}
+
+// FormatLRC собирает строки обратно в LRC. Для строк с пословной разметкой
+// используется расширенный формат.
+func FormatLRC(lines []models.SyncedLine) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString("[" + lrcTimestamp(line.Time) + "]")
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		} else {
+			for i, w := range line.Words {
+				if i > 0 {
+					b.WriteString(" ")
+				}
+				b.WriteString("<" + lrcTimestamp(w.Time) + ">" + w.Text)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SyncedLineAt возвращает строку, которая звучит в момент ms (последняя строка
+// с временем не больше ms). Если ms раньше первой строки, ok будет false.
+func SyncedLineAt(lines []models.SyncedLine, ms int64) (models.SyncedLineAt, bool) {
+	i := sort.Search(len(lines), func(i int) bool { return lines[i].Time > ms }) - 1
+	if i < 0 {
+		return models.SyncedLineAt{}, false
+	}
+	at := models.SyncedLineAt{Index: i, Line: lines[i]}
+	if i+1 < len(lines) {
+		next := lines[i+1].Time
+		at.NextTime = &next
+	}
+	return at, true
+}
+
+// SyncedToText собирает из синхронизированных строк обычный текст песни.
+// Пустые строки (паузы) разделяют куплеты.
+func SyncedToText(lines []models.SyncedLine) string {
+	var verses []string
+	var current []string
+	for _, line := range lines {
+		if strings.TrimSpace(line.Text) == "" {
+			if len(current) > 0 {
+				verses = append(verses, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line.Text)
+	}
+	if len(current) > 0 {
+		verses = append(verses, strings.Join(current, "\n"))
+	}
+	return strings.Join(verses, "\n\n")
+}
+
+func lrcMillis(minutes, seconds, fraction string) (int64, error) {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+	if s >= 60 {
+		return 0, fmt.Errorf("некорректная метка времени %s:%s", minutes, seconds)
+	}
+	ms := (m*60 + s) * 1000
+	if fraction != "" {
+		f, _ := strconv.ParseInt(fraction, 10, 64)
+		switch len(fraction) {
+		case 1:
+			f *= 100
+		case 2:
+			f *= 10
+		}
+		ms += f
+	}
+	return ms, nil
+}
+
+func lrcTimestamp(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
+
+func submatch(s string, loc []int, i int) string {
+	if loc[i] < 0 {
+		return ""
+	}
+	return s[loc[i]:loc[i+1]]
+}
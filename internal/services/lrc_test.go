@@ -0,0 +1,125 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"songs/internal/models"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		want         []models.SyncedLine
+		wantEnhanced bool
+		wantErr      bool
+	}{
+		{
+			name: "простые строки",
+			text: "[00:12.34]Hello\n[00:15.5]World\n[01:02.345]Again",
+			want: []models.SyncedLine{{Time: 12340, Text: "Hello"}, {Time: 15500, Text: "World"}, {Time: 62345, Text: "Again"}},
+		},
+		{
+			name: "BOM, CRLF и метаданные",
+			text: "\ufeff[ar:Muse]\r\n[ti:Uprising]\r\n\r\n[00:01.00]A\r\n[00:02:50]B",
+			want: []models.SyncedLine{{Time: 1000, Text: "A"}, {Time: 2500, Text: "B"}},
+		},
+		{
+			name: "несколько меток на строке",
+			text: "[00:10.00][00:30.00]Chorus\n[00:20.00]Verse",
+			want: []models.SyncedLine{{Time: 10000, Text: "Chorus"}, {Time: 20000, Text: "Verse"}, {Time: 30000, Text: "Chorus"}},
+		},
+		{
+			name: "положительный offset сдвигает раньше, но не меньше нуля",
+			text: "[offset:500]\n[00:01.00]A\n[00:00.20]B",
+			want: []models.SyncedLine{{Time: 0, Text: "B"}, {Time: 500, Text: "A"}},
+		},
+		{
+			name: "отрицательный offset",
+			text: "[offset:-250]\n[00:01.000]A",
+			want: []models.SyncedLine{{Time: 1250, Text: "A"}},
+		},
+		{
+			name:         "пословные метки",
+			text:         "[00:01.00]<00:01.00>Hello <00:01.50>world",
+			want:         []models.SyncedLine{{Time: 1000, Text: "Hello world", Words: []models.SyncedWord{{Time: 1000, Text: "Hello"}, {Time: 1500, Text: "world"}}}},
+			wantEnhanced: true,
+		},
+		{
+			name:         "слова до первой пословной метки получают время строки",
+			text:         "[00:05.00]Oh <00:06.00>yeah",
+			want:         []models.SyncedLine{{Time: 5000, Text: "Oh yeah", Words: []models.SyncedWord{{Time: 5000, Text: "Oh"}, {Time: 6000, Text: "yeah"}}}},
+			wantEnhanced: true,
+		},
+		{
+			name: "пословные метки сдвигаются вместе с повтором строки и offset",
+			text: "[offset:100]\n[00:01.00][00:11.00]<00:01.00>Hi <00:02.00>there",
+			want: []models.SyncedLine{
+				{Time: 900, Text: "Hi there", Words: []models.SyncedWord{{Time: 900, Text: "Hi"}, {Time: 1900, Text: "there"}}},
+				{Time: 10900, Text: "Hi there", Words: []models.SyncedWord{{Time: 10900, Text: "Hi"}, {Time: 11900, Text: "there"}}},
+			},
+			wantEnhanced: true,
+		},
+		{name: "строка без метки", text: "[00:01.00]A\nB", wantErr: true},
+		{name: "секунды больше 59", text: "[00:61.00]A", wantErr: true},
+		{name: "некорректный offset", text: "[offset:abc]\n[00:01.00]A", wantErr: true},
+		{name: "слово раньше строки", text: "[00:05.00]<00:04.00>A", wantErr: true},
+		{name: "пословные метки не по возрастанию", text: "[00:05.00]<00:06.00>A <00:05.50>B", wantErr: true},
+		{name: "только метаданные", text: "[ar:Muse]\n[ti:Uprising]", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, enhanced, err := ParseLRC(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ParseLRC error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ParseLRC = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if enhanced != tt.wantEnhanced {
+			t.Errorf("%s: ParseLRC enhanced = %v, want %v", tt.name, enhanced, tt.wantEnhanced)
+		}
+	}
+}
+
+func TestFormatLRC(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []models.SyncedLine
+		want  string
+	}{
+		{name: "пусто", want: ""},
+		{
+			name:  "простые строки",
+			lines: []models.SyncedLine{{Time: 12340, Text: "Hello"}, {Time: 15505, Text: "World"}},
+			want:  "[00:12.34]Hello\n[00:15.50]World\n",
+		},
+		{
+			name:  "больше часа",
+			lines: []models.SyncedLine{{Time: 3723450, Text: "Outro"}},
+			want:  "[62:03.45]Outro\n",
+		},
+		{
+			name:  "пословные метки",
+			lines: []models.SyncedLine{{Time: 1000, Text: "Hello world", Words: []models.SyncedWord{{Time: 1000, Text: "Hello"}, {Time: 1500, Text: "world"}}}},
+			want:  "[00:01.00]<00:01.00>Hello <00:01.50>world\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := FormatLRC(tt.lines); got != tt.want {
+			t.Errorf("%s: FormatLRC = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatLRCRoundTrip(t *testing.T) {
+	text := "[00:01.00]<00:01.00>Hello <00:01.50>world\n[00:03.25]Plain line\n[00:10.00]<00:10.00>Again <00:10.75>and <00:11.00>again\n"
+	lines, _, err := ParseLRC(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatLRC(lines); got != text {
+		t.Errorf("FormatLRC(ParseLRC(text)) = %q, want %q", got, text)
+	}
+}
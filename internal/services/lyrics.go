@@ -0,0 +1,199 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"songs/internal/models"
+)
+
+const (
+	SectionVerse     = "verse"
+	SectionChorus    = "chorus"
+	SectionPreChorus = "prechorus"
+	SectionBridge    = "bridge"
+	SectionIntro     = "intro"
+	SectionOutro     = "outro"
+	SectionOther     = "other"
+)
+
+// sectionMarker распознаёт строки-метки вида [Chorus], [Куплет 2], (Bridge).
+// Метки в круглых скобках дополнительно проверяются parenSectionLabel.
+var sectionMarker = regexp.MustCompile(`^[\[(]\s*([^\])]+?)\s*[\])]$`)
+
+// Ключевые слова меток и соответствующие типы секций. Порядок важен:
+// pre-chorus должен проверяться раньше chorus.
+var sectionKeywords = []struct {
+	keyword string
+	kind    string
+}{
+	{"pre-chorus", SectionPreChorus},
+	{"pre chorus", SectionPreChorus},
+	{"prechorus", SectionPreChorus},
+	{"предприпев", SectionPreChorus},
+	{"chorus", SectionChorus},
+	{"refrain", SectionChorus},
+	{"hook", SectionChorus},
+	{"припев", SectionChorus},
+	{"verse", SectionVerse},
+	{"куплет", SectionVerse},
+	{"bridge", SectionBridge},
+	{"бридж", SectionBridge},
+	{"intro", SectionIntro},
+	{"вступление", SectionIntro},
+	{"outro", SectionOutro},
+	{"концовка", SectionOutro},
+	{"кода", SectionOutro},
+}
+
+// parenSectionLabel — метка в круглых скобках: ключевое слово, за которым
+// может идти номер и число повторов: (Chorus), (Verse 2), (Chorus x2).
+var parenSectionLabel = func() *regexp.Regexp {
+	keywords := make([]string, len(sectionKeywords))
+	for i, k := range sectionKeywords {
+		keywords[i] = regexp.QuoteMeta(k.keyword)
+	}
+	return regexp.MustCompile(`^(?:` + strings.Join(keywords, "|") + `)(?:\s*\d+)?(?:\s*[:x×]\s*\d+)?$`)
+}()
+
+// ParseLyrics разбирает текст песни на типизированные секции. Секции разделяются
+// пустыми строками или метками вида [Chorus]. Блоки без метки считаются куплетами,
+// а блоки, текст которых повторяется, — припевами. Метка без строк (например,
+// повторный [Chorus]) повторяет последнюю секцию с той же меткой.
+func ParseLyrics(text string) []models.LyricsSection {
+	type block struct {
+		label string
+		kind  string
+		lines []string
+	}
+
+	var blocks []block
+	var current *block
+	flush := func() {
+		if current != nil && (len(current.lines) > 0 || current.label != "") {
+			blocks = append(blocks, *current)
+		}
+		current = nil
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			flush()
+			continue
+		}
+		if m := sectionMarker.FindStringSubmatch(line); m != nil {
+			// Строка в круглых скобках — метка, только если она целиком состоит
+			// из ключевого слова; иначе это подпевка вроде (Hey!), (x2) или
+			// (hooked on a feeling), часть текста.
+			if line[0] == '[' || parenSectionLabel.MatchString(strings.ToLower(m[1])) {
+				flush()
+				current = &block{label: m[1], kind: sectionKind(m[1])}
+				continue
+			}
+		}
+		if current == nil {
+			current = &block{}
+		}
+		current.lines = append(current.lines, line)
+	}
+	flush()
+
+	// Повторяющиеся блоки без метки (или совпадающие с размеченным припевом) — припевы.
+	counts := make(map[string]int)
+	choruses := make(map[string]bool)
+	for _, b := range blocks {
+		key := blockKey(b.lines)
+		if key == "" {
+			continue
+		}
+		counts[key]++
+		if b.kind == SectionChorus {
+			choruses[key] = true
+		}
+	}
+
+	lastByLabel := make(map[string][]string)
+	numbers := make(map[string]int)
+	sections := make([]models.LyricsSection, 0, len(blocks))
+	for _, b := range blocks {
+		key := blockKey(b.lines)
+		kind := b.kind
+		if b.label == "" {
+			kind = SectionVerse
+			if counts[key] > 1 || choruses[key] {
+				kind = SectionChorus
+			}
+		}
+
+		lines := b.lines
+		labelKey := strings.ToLower(b.label)
+		if len(lines) == 0 {
+			lines = lastByLabel[labelKey]
+			if len(lines) == 0 {
+				continue
+			}
+		} else if labelKey != "" {
+			lastByLabel[labelKey] = lines
+		}
+
+		numbers[kind]++
+		sections = append(sections, models.LyricsSection{
+			Type:   kind,
+			Index:  len(sections),
+			Number: numbers[kind],
+			Label:  b.label,
+			Lines:  lines,
+		})
+	}
+	return sections
+}
+
+// FilterSections оставляет только секции указанного типа. Пустой тип не фильтрует.
+func FilterSections(sections []models.LyricsSection, kind string) []models.LyricsSection {
+	if kind == "" {
+		return sections
+	}
+	filtered := make([]models.LyricsSection, 0, len(sections))
+	for _, s := range sections {
+		if s.Type == kind {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// sectionKind определяет тип секции по ключевому слову метки. Слово должно
+// стоять отдельно: [Verse 1: Кино] — куплет, а [Universe] — нет.
+func sectionKind(label string) string {
+	label = strings.ToLower(label)
+	for _, k := range sectionKeywords {
+		if containsWord(label, k.keyword) {
+			return k.kind
+		}
+	}
+	return SectionOther
+}
+
+func containsWord(s, word string) bool {
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !unicode.IsLetter(before) && !unicode.IsLetter(after) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func blockKey(lines []string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(lines, " ")), " "))
+}
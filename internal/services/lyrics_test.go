@@ -0,0 +1,84 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLyrics(t *testing.T) {
+	type section struct {
+		kind  string
+		label string
+		lines []string
+	}
+	tests := []struct {
+		name string
+		text string
+		want []section
+	}{
+		{
+			name: "square markers",
+			text: "[Verse 1]\nline a\n\n[Chorus]\nline b\n\n[Chorus]",
+			want: []section{
+				{SectionVerse, "Verse 1", []string{"line a"}},
+				{SectionChorus, "Chorus", []string{"line b"}},
+				{SectionChorus, "Chorus", []string{"line b"}},
+			},
+		},
+		{
+			name: "paren markers",
+			text: "(Intro)\nline a\n(Chorus x2)\nline b\n(Verse 2)\nline c\n(Pre-Chorus)\nline d",
+			want: []section{
+				{SectionIntro, "Intro", []string{"line a"}},
+				{SectionChorus, "Chorus x2", []string{"line b"}},
+				{SectionVerse, "Verse 2", []string{"line c"}},
+				{SectionPreChorus, "Pre-Chorus", []string{"line d"}},
+			},
+		},
+		{
+			name: "paren ad-libs stay lyrics",
+			text: "line a\n(Hey!)\n(x2)\n(hooked on a feeling)\n(universe)\n(introduction)\n(bridges burning)",
+			want: []section{
+				{SectionVerse, "", []string{"line a", "(Hey!)", "(x2)", "(hooked on a feeling)", "(universe)", "(introduction)", "(bridges burning)"}},
+			},
+		},
+		{
+			name: "square marker without keyword",
+			text: "[Universe]\nline a",
+			want: []section{
+				{SectionOther, "Universe", []string{"line a"}},
+			},
+		},
+		{
+			name: "russian markers and featured artist",
+			text: "[Куплет 1: Кино]\nline a\n\n[Припев]\nline b",
+			want: []section{
+				{SectionVerse, "Куплет 1: Кино", []string{"line a"}},
+				{SectionChorus, "Припев", []string{"line b"}},
+			},
+		},
+		{
+			name: "repeated unlabelled block is chorus",
+			text: "line a\n\nline b\n\nline a\r\n",
+			want: []section{
+				{SectionChorus, "", []string{"line a"}},
+				{SectionVerse, "", []string{"line b"}},
+				{SectionChorus, "", []string{"line a"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []section
+			for i, s := range ParseLyrics(tt.text) {
+				if s.Index != i {
+					t.Errorf("section %d: Index = %d", i, s.Index)
+				}
+				got = append(got, section{s.Type, s.Label, s.Lines})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLyrics() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
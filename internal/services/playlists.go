@@ -0,0 +1,339 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Шаг между позициями соседних элементов плейлиста. Вставка между двумя
+	// элементами берёт середину интервала, поэтому остальные элементы не сдвигаются.
+	playlistPositionStep = 1024.0
+	// Если соседние позиции сблизились сильнее, плейлист перенумеровывается целиком.
+	playlistMinPositionGap = 1e-6
+)
+
+var (
+	ErrOwnerRequired            = errors.New("Не указан пользователь, передайте заголовок X-User-ID")
+	ErrPlaylistNotFound         = errors.New("Плейлист не найден")
+	ErrPlaylistForbidden        = errors.New("Изменять плейлист может только его владелец")
+	ErrInvalidPlaylist          = errors.New("Название плейлиста не может быть пустым")
+	ErrPlaylistItemNotFound     = errors.New("Элемент плейлиста не найден")
+	ErrInvalidPlaylistItemIndex = errors.New("Индекс элемента плейлиста не может быть отрицательным")
+)
+
+// CreatePlaylist создаёт пустой плейлист пользователя.
+func CreatePlaylist(db *gorm.DB, ownerID string, input models.PlaylistInput) (models.Playlist, error) {
+	if ownerID == "" {
+		return models.Playlist{}, ErrOwnerRequired
+	}
+	if strings.TrimSpace(input.Title) == "" {
+		return models.Playlist{}, ErrInvalidPlaylist
+	}
+	playlist := models.Playlist{
+		OwnerID:     ownerID,
+		Title:       strings.TrimSpace(input.Title),
+		Description: input.Description,
+		Public:      input.Public,
+	}
+	if err := db.Omit("Items").Create(&playlist).Error; err != nil {
+		return playlist, err
+	}
+	return playlist, nil
+}
+
+// ListPlaylists возвращает плейлисты пользователя без элементов.
+func ListPlaylists(db *gorm.DB, ownerID string) ([]models.Playlist, error) {
+	if ownerID == "" {
+		return nil, ErrOwnerRequired
+	}
+	playlists := []models.Playlist{}
+	err := db.Where("owner_id = ?", ownerID).Order("updated_at DESC").Find(&playlists).Error
+	return playlists, err
+}
+
+// FindPlaylist возвращает плейлист с элементами по порядку, если его может видеть
+// пользователь: владельцу доступны все его плейлисты, остальным только публичные.
+// Чужие приватные плейлисты неотличимы от несуществующих.
+func FindPlaylist(db *gorm.DB, id uint, viewerID string) (models.Playlist, error) {
+	var playlist models.Playlist
+	if err := db.First(&playlist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return playlist, ErrPlaylistNotFound
+		}
+		return playlist, err
+	}
+	if playlist.OwnerID != viewerID && !playlist.Public {
+		return models.Playlist{}, ErrPlaylistNotFound
+	}
+	return playlistWithItems(db, playlist, viewerID)
+}
+
+// FindSharedPlaylist возвращает плейлист по ссылке для шаринга, в том числе приватный.
+func FindSharedPlaylist(db *gorm.DB, token, viewerID string) (models.Playlist, error) {
+	var playlist models.Playlist
+	if err := db.Where("share_token = ?", token).First(&playlist).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return playlist, ErrPlaylistNotFound
+		}
+		return playlist, err
+	}
+	return playlistWithItems(db, playlist, viewerID)
+}
+
+// UpdatePlaylist меняет название, описание или видимость плейлиста.
+func UpdatePlaylist(db *gorm.DB, id uint, ownerID string, input models.PlaylistUpdate) (models.Playlist, error) {
+	playlist, err := ownedPlaylist(db, id, ownerID)
+	if err != nil {
+		return playlist, err
+	}
+	if input.Title != nil {
+		if strings.TrimSpace(*input.Title) == "" {
+			return playlist, ErrInvalidPlaylist
+		}
+		playlist.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Description != nil {
+		playlist.Description = *input.Description
+	}
+	if input.Public != nil {
+		playlist.Public = *input.Public
+	}
+	if err := db.Omit("Items").Save(&playlist).Error; err != nil {
+		return playlist, err
+	}
+	return playlistWithItems(db, playlist, ownerID)
+}
+
+// DeletePlaylist удаляет плейлист вместе с элементами.
+func DeletePlaylist(db *gorm.DB, id uint, ownerID string) error {
+	playlist, err := ownedPlaylist(db, id, ownerID)
+	if err != nil {
+		return err
+	}
+	return db.Delete(&playlist).Error
+}
+
+// SharePlaylist выдаёт новую ссылку для шаринга. Старая ссылка перестаёт работать.
+func SharePlaylist(db *gorm.DB, id uint, ownerID string) (string, error) {
+	playlist, err := ownedPlaylist(db, id, ownerID)
+	if err != nil {
+		return "", err
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return "", err
+	}
+	if err := db.Model(&playlist).Update("share_token", token).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// UnsharePlaylist отзывает ссылку для шаринга.
+func UnsharePlaylist(db *gorm.DB, id uint, ownerID string) error {
+	playlist, err := ownedPlaylist(db, id, ownerID)
+	if err != nil {
+		return err
+	}
+	return db.Model(&playlist).Update("share_token", nil).Error
+}
+
+// AddPlaylistItem вставляет песню в плейлист по индексу (nil — в конец).
+// Одна и та же песня может встречаться в плейлисте несколько раз.
+func AddPlaylistItem(db *gorm.DB, id uint, ownerID string, input models.PlaylistItemInput) (models.Playlist, error) {
+	playlist, err := lockOwnedPlaylist(db, id, ownerID)
+	if err != nil {
+		return playlist, err
+	}
+	if err := db.Select("id").First(&models.Song{}, input.SongID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return playlist, ErrSongNotFound
+		}
+		return playlist, err
+	}
+
+	position, err := playlistPositionAt(db, playlist.ID, input.Index, 0)
+	if err != nil {
+		return playlist, err
+	}
+	item := models.PlaylistItem{PlaylistID: playlist.ID, SongID: input.SongID, Position: position}
+	if err := db.Omit("Playlist", "Song").Create(&item).Error; err != nil {
+		return playlist, err
+	}
+	return touchPlaylist(db, playlist, ownerID)
+}
+
+// MovePlaylistItem переставляет элемент на указанный индекс. Меняется позиция
+// только самого элемента.
+func MovePlaylistItem(db *gorm.DB, id, itemID uint, ownerID string, index *int) (models.Playlist, error) {
+	playlist, err := lockOwnedPlaylist(db, id, ownerID)
+	if err != nil {
+		return playlist, err
+	}
+	var item models.PlaylistItem
+	if err := db.Where("id = ? AND playlist_id = ?", itemID, playlist.ID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return playlist, ErrPlaylistItemNotFound
+		}
+		return playlist, err
+	}
+
+	position, err := playlistPositionAt(db, playlist.ID, index, item.ID)
+	if err != nil {
+		return playlist, err
+	}
+	if err := db.Model(&item).Update("position", position).Error; err != nil {
+		return playlist, err
+	}
+	return touchPlaylist(db, playlist, ownerID)
+}
+
+// RemovePlaylistItem удаляет элемент плейлиста. Остальные элементы не сдвигаются.
+func RemovePlaylistItem(db *gorm.DB, id, itemID uint, ownerID string) (models.Playlist, error) {
+	playlist, err := lockOwnedPlaylist(db, id, ownerID)
+	if err != nil {
+		return playlist, err
+	}
+	result := db.Where("id = ? AND playlist_id = ?", itemID, playlist.ID).Delete(&models.PlaylistItem{})
+	if result.Error != nil {
+		return playlist, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return playlist, ErrPlaylistItemNotFound
+	}
+	return touchPlaylist(db, playlist, ownerID)
+}
+
+// playlistPositionAt вычисляет позицию для вставки элемента по индексу: середину
+// между соседями, шаг до первого или после последнего. excludeID — перемещаемый
+// элемент, он не считается соседом.
+func playlistPositionAt(db *gorm.DB, playlistID uint, index *int, excludeID uint) (float64, error) {
+	if index != nil && *index < 0 {
+		return 0, ErrInvalidPlaylistItemIndex
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		neighbours := db.Model(&models.PlaylistItem{}).
+			Where("playlist_id = ? AND id <> ?", playlistID, excludeID)
+
+		var positions []float64
+		var err error
+		switch {
+		case index == nil:
+			err = neighbours.Order("position DESC, id DESC").Limit(1).Pluck("position", &positions).Error
+			if err == nil && len(positions) > 0 {
+				return positions[0] + playlistPositionStep, nil
+			}
+		case *index == 0:
+			err = neighbours.Order("position, id").Limit(1).Pluck("position", &positions).Error
+			if err == nil && len(positions) > 0 {
+				return positions[0] - playlistPositionStep, nil
+			}
+		default:
+			err = neighbours.Order("position, id").Offset(*index-1).Limit(2).Pluck("position", &positions).Error
+			if err == nil && len(positions) == 1 {
+				return positions[0] + playlistPositionStep, nil
+			}
+			if err == nil && len(positions) == 2 {
+				if positions[1]-positions[0] >= playlistMinPositionGap {
+					return (positions[0] + positions[1]) / 2, nil
+				}
+				// Соседи слишком близко: перенумеровываем и считаем заново.
+				if err := rebalancePlaylist(db, playlistID); err != nil {
+					return 0, err
+				}
+				continue
+			}
+			if err == nil {
+				// Индекс за концом плейлиста — добавляем в конец.
+				index = nil
+				continue
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		return playlistPositionStep, nil
+	}
+	return 0, errors.New("не удалось вычислить позицию элемента плейлиста")
+}
+
+// rebalancePlaylist перенумеровывает элементы плейлиста с шагом playlistPositionStep,
+// сохраняя порядок.
+func rebalancePlaylist(db *gorm.DB, playlistID uint) error {
+	logger.Log.Infof("Перенумерация элементов плейлиста id: %d", playlistID)
+	return db.Exec(`UPDATE playlist_items SET position = ordered.rn * ?
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS rn FROM playlist_items WHERE playlist_id = ?) AS ordered
+		WHERE playlist_items.id = ordered.id`, playlistPositionStep, playlistID).Error
+}
+
+func playlistWithItems(db *gorm.DB, playlist models.Playlist, viewerID string) (models.Playlist, error) {
+	err := db.Preload("Song").Preload("Song.Artist").
+		Where("playlist_id = ?", playlist.ID).
+		Order("position, id").
+		Find(&playlist.Items).Error
+	if err != nil {
+		return playlist, err
+	}
+	for i := range playlist.Items {
+		playlist.Items[i].Index = i
+	}
+	// Ссылку для шаринга видит только владелец.
+	if playlist.OwnerID != viewerID {
+		playlist.ShareToken = nil
+	}
+	return playlist, nil
+}
+
+// lockOwnedPlaylist блокирует строку плейлиста до конца транзакции, чтобы
+// параллельные вставки не получили одинаковые позиции.
+func lockOwnedPlaylist(db *gorm.DB, id uint, ownerID string) (models.Playlist, error) {
+	return ownedPlaylist(db.Clauses(clause.Locking{Strength: "UPDATE"}), id, ownerID)
+}
+
+// ownedPlaylist возвращает плейлист, если его владелец ownerID. Чужой приватный
+// плейлист неотличим от несуществующего.
+func ownedPlaylist(db *gorm.DB, id uint, ownerID string) (models.Playlist, error) {
+	if ownerID == "" {
+		return models.Playlist{}, ErrOwnerRequired
+	}
+	var playlist models.Playlist
+	if err := db.First(&playlist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return playlist, ErrPlaylistNotFound
+		}
+		return playlist, err
+	}
+	if playlist.OwnerID != ownerID {
+		if !playlist.Public {
+			return models.Playlist{}, ErrPlaylistNotFound
+		}
+		return models.Playlist{}, ErrPlaylistForbidden
+	}
+	return playlist, nil
+}
+
+func touchPlaylist(db *gorm.DB, playlist models.Playlist, ownerID string) (models.Playlist, error) {
+	if err := db.Model(&playlist).Update("updated_at", time.Now()).Error; err != nil {
+		return playlist, err
+	}
+	return playlistWithItems(db, playlist, ownerID)
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"math"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/search"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Порог сходства для поиска: ниже стандартных 0.6 из pg_trgm, чтобы находить
+	// названия с опечатками. SET не принимает параметры, поэтому значения в тексте запроса.
+	setSearchThreshold = "SET LOCAL pg_trgm.word_similarity_threshold = 0.35"
+	// Порог для подсказки «возможно, вы имели в виду».
+	setSuggestionThreshold = "SET LOCAL pg_trgm.similarity_threshold = 0.2"
+)
+
+var ErrEmptyQuery = errors.New("Пустой поисковый запрос")
+
+// SearchSongs ищет песни по названию и имени артиста с учётом опечаток,
+// диакритики и транслитерации. Результаты отсортированы по убыванию сходства.
+// Если ничего не найдено, возвращается подсказка с ближайшим известным названием.
+func SearchSongs(db *gorm.DB, query string, limit int) (models.SongSearchResponse, error) {
+	response := models.SongSearchResponse{Query: query, Results: []models.SongSearchResult{}}
+	key := search.Key(query)
+	if key == "" {
+		return response, ErrEmptyQuery
+	}
+
+	type scoredSong struct {
+		ID    uint
+		Score float64
+	}
+	var scored []scoredSong
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(setSearchThreshold).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`SELECT songs.id,
+				GREATEST(word_similarity(@key, songs.search_key), word_similarity(@key, artists.search_key),
+					similarity(songs.search_key || ' ' || artists.search_key, @key)) AS score
+			FROM songs JOIN artists ON artists.id = songs.artist_id
+			WHERE @key <% songs.search_key OR @key <% artists.search_key
+				OR songs.search_key LIKE @pattern OR artists.search_key LIKE @pattern
+			ORDER BY score DESC, songs.id
+			LIMIT @limit`,
+			map[string]interface{}{"key": key, "pattern": search.Contains(key), "limit": limit}).
+			Scan(&scored).Error
+	})
+	if err != nil {
+		return response, err
+	}
+
+	if len(scored) == 0 {
+		suggestion, err := Suggestion(db, query)
+		if err != nil {
+			logger.Log.Errorf("Ошибка подбора подсказки: %v", err)
+		}
+		response.DidYouMean = suggestion
+		return response, nil
+	}
+
+	ids := make([]uint, len(scored))
+	for i, s := range scored {
+		ids[i] = s.ID
+	}
+	var songs []models.Song
+	if err := db.Preload("Artist").Where("id IN ?", ids).Find(&songs).Error; err != nil {
+		return response, err
+	}
+	byID := make(map[uint]models.Song, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+	}
+	for _, s := range scored {
+		if song, ok := byID[s.ID]; ok {
+			response.Results = append(response.Results, models.SongSearchResult{
+				Score: math.Round(s.Score*1000) / 1000,
+				Song:  song,
+			})
+		}
+	}
+	return response, nil
+}
+
+// Suggestion подбирает название песни или имя артиста, ближайшее к запросу.
+// Пустая строка — подходящего варианта нет или он совпадает с запросом.
+func Suggestion(db *gorm.DB, query string) (string, error) {
+	key := search.Key(query)
+	if key == "" {
+		return "", nil
+	}
+
+	var suggestion string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(setSuggestionThreshold).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`SELECT term FROM (
+				SELECT name AS term, search_key FROM artists WHERE search_key % @key
+				UNION ALL
+				SELECT song AS term, search_key FROM songs WHERE search_key % @key
+			) AS candidates
+			ORDER BY similarity(search_key, @key) DESC, LENGTH(term)
+			LIMIT 1`, map[string]interface{}{"key": key}).
+			Scan(&suggestion).Error
+	})
+	if err != nil || search.Key(suggestion) == key {
+		return "", err
+	}
+	return suggestion, nil
+}
+
+// BackfillSearchKeys заполняет ключи поиска у песен и артистов, созданных
+// до появления нечёткого поиска.
+func BackfillSearchKeys(db *gorm.DB) error {
+	var artists []models.Artist
+	err := db.Select("id", "name").Where("search_key = ''").
+		FindInBatches(&artists, 500, func(tx *gorm.DB, batch int) error {
+			for _, artist := range artists {
+				if err := db.Model(&artist).UpdateColumn("search_key", search.Key(artist.Name)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	var songs []models.Song
+	return db.Select("id", "song").Where("search_key = ''").
+		FindInBatches(&songs, 500, func(tx *gorm.DB, batch int) error {
+			for _, song := range songs {
+				if err := db.Model(&song).UpdateColumn("search_key", search.Key(song.Song)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"songs/config"
 	"songs/internal/logger"
@@ -47,15 +46,3 @@ func FetchSongDetail(group, songTitle string) (*models.SongDetail, error) {
 	logger.Log.Info("Успешно получены данные о песне с внешнего API")
 	return &detail, nil
 }
-
-func SplitVerses(text string) []string {
-	verses := strings.Split(text, "\n\n")
-	var result []string
-	for _, v := range verses {
-		v = strings.TrimSpace(v)
-		if v != "" {
-			result = append(result, v)
-		}
-	}
-	return result
-}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSongNotFound = errors.New("Песня не найдена")
+	ErrInvalidInput = errors.New("Поля group и song обязательны")
+
+	ErrDeletedSongNotFound = errors.New("Удалённая песня не найдена в журнале событий")
+	ErrSongExists          = errors.New("Песня с таким ID уже существует")
+	ErrGroupWithMoveTo     = errors.New("Поля group и moveTo нельзя передавать вместе")
+)
+
+// CreateSong сохраняет новую песню с данными, полученными из внешнего API.
+// Строка группы вида "A feat. B" разбирается на основного и приглашённых
+// исполнителей; артисты находятся по имени или создаются.
+func CreateSong(db *gorm.DB, group, songTitle string, detail *models.SongDetail) (models.Song, error) {
+	if strings.TrimSpace(group) == "" || strings.TrimSpace(songTitle) == "" {
+		return models.Song{}, ErrInvalidInput
+	}
+
+	credits, err := ResolveGroupCredits(db, group)
+	if err != nil {
+		return models.Song{}, err
+	}
+	artist := credits[0].Artist
+
+	// Неразобранная дата не мешает добавлению: песня сохраняется с неизвестной датой.
+	parsedDate, err := ParseReleaseDate(detail.ReleaseDate)
+	if err != nil {
+		logger.Log.Errorf("ошибка парсинга даты: %v", err)
+	}
+
+	newSong := models.Song{
+		ArtistID:    artist.ID,
+		Artist:      artist,
+		Song:        songTitle,
+		ReleaseDate: parsedDate,
+		Text:        detail.Text,
+		Link:        detail.Link,
+	}
+	logger.Log.Debugf("Песня: %v", newSong)
+
+	if err := db.Omit("Artist", "Links", "Credits", "Genres", "Tags").Create(&newSong).Error; err != nil {
+		return models.Song{}, err
+	}
+	if err := SaveGroupCredits(db, newSong.ID, credits); err != nil {
+		return models.Song{}, err
+	}
+	newSong.Credits = credits
+	newSong.Links, err = AddSongLinks(db, newSong.ID, append([]string{detail.Link}, detail.Links...)...)
+	if err != nil {
+		return models.Song{}, err
+	}
+	if err := PublishSongEvent(db, EventSongCreated, newSong.ID); err != nil {
+		return models.Song{}, err
+	}
+	return newSong, nil
+}
+
+// UpdateSong применяет к песне переданные поля. Поле group переименовывает
+// основного исполнителя песни, и новое имя видно во всех его песнях. Поле moveTo
+// переносит песню к артисту с этим именем (он создаётся при необходимости), а
+// основной и приглашённые исполнители пересобираются из строки группы.
+func UpdateSong(db *gorm.DB, id uint, input models.SongUpdate) (models.Song, error) {
+	if input.GroupName != nil && input.MoveTo != nil {
+		return models.Song{}, ErrGroupWithMoveTo
+	}
+
+	var song models.Song
+	if err := db.Preload("Artist").Preload("Links").First(&song, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return song, ErrSongNotFound
+		}
+		return song, err
+	}
+	logger.Log.Debugf("Исходная песня: %+v", song)
+
+	if input.GroupName != nil {
+		if strings.TrimSpace(*input.GroupName) == "" {
+			return song, ErrInvalidInput
+		}
+		artist, err := RenameArtist(db, song.ArtistID, *input.GroupName)
+		if err != nil {
+			return song, err
+		}
+		song.Artist = artist
+	}
+	if input.MoveTo != nil {
+		if strings.TrimSpace(*input.MoveTo) == "" {
+			return song, ErrInvalidInput
+		}
+		credits, err := ResolveGroupCredits(db, *input.MoveTo)
+		if err != nil {
+			return song, err
+		}
+		if err := SaveGroupCredits(db, song.ID, credits); err != nil {
+			return song, err
+		}
+		song.ArtistID = credits[0].ArtistID
+		song.Artist = credits[0].Artist
+	}
+	if input.Song != nil {
+		if strings.TrimSpace(*input.Song) == "" {
+			return song, ErrInvalidInput
+		}
+		song.Song = *input.Song
+	}
+	if input.ReleaseDate != nil {
+		song.ReleaseDate = input.ReleaseDate
+		if input.ReleaseDate.IsZero() {
+			song.ReleaseDate = nil
+		}
+	}
+	if input.Text != nil {
+		song.Text = *input.Text
+	}
+	if input.Link != nil {
+		song.Link = *input.Link
+	}
+	if input.Lang != nil {
+		song.Lang = ""
+		if *input.Lang != "" {
+			lang, err := NormalizeLang(*input.Lang)
+			if err != nil {
+				return song, err
+			}
+			song.Lang = lang
+		}
+	}
+
+	if err := db.Omit("Artist", "Links", "Credits", "Genres", "Tags").Save(&song).Error; err != nil {
+		return song, err
+	}
+	if input.Link != nil && *input.Link != "" {
+		links, err := AddSongLinks(db, song.ID, *input.Link)
+		if err != nil {
+			return song, err
+		}
+		song.Links = links
+	}
+	credits, err := FindSongCredits(db, song.ID)
+	if err != nil {
+		return song, err
+	}
+	song.Credits = credits
+	if err := PublishSongEvent(db, EventSongUpdated, song.ID); err != nil {
+		return song, err
+	}
+	return song, nil
+}
+
+// DeleteSong удаляет песню по ID. В событие song.deleted попадает карточка
+// песни на момент удаления, а в song_archives — данные, которые удаляются
+// каскадом, чтобы RestoreSong мог их вернуть.
+func DeleteSong(db *gorm.DB, id uint) error {
+	song, err := findSongSnapshot(db, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSongNotFound
+		}
+		return err
+	}
+	if err := archiveSong(db, id); err != nil {
+		return err
+	}
+	result := db.Delete(&models.Song{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSongNotFound
+	}
+	return PublishEvent(db, EventSongDeleted, id, song)
+}
+
+// RestoreSong восстанавливает удалённую песню по её последнему событию song.deleted
+// из catalog_events: с тем же ID, полями, ссылками, участниками, жанрами и тегами.
+// Артисты, которых с тех пор не стало (например, после объединения), находятся или
+// создаются заново по имени; удалённые жанры и теги пропускаются. Переводы,
+// синхронизированный текст, места в альбомах и плейлистах, избранное и статистика
+// возвращаются из song_archives (см. restoreSongArchive).
+func RestoreSong(db *gorm.DB, id uint) (models.Song, error) {
+	var count int64
+	if err := db.Model(&models.Song{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return models.Song{}, err
+	}
+	if count > 0 {
+		return models.Song{}, ErrSongExists
+	}
+
+	var event models.Event
+	err := db.Where("entity_type = ? AND entity_id = ? AND type = ?", "song", id, EventSongDeleted).
+		Order("seq DESC").First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Song{}, ErrDeletedSongNotFound
+	}
+	if err != nil {
+		return models.Song{}, err
+	}
+	var snapshot models.Song
+	if err := json.Unmarshal(event.Data, &snapshot); err != nil {
+		return models.Song{}, err
+	}
+	logger.Log.Infof("Восстановление песни %d из события %d", id, event.Seq)
+
+	artist, err := restoreArtist(db, snapshot.Artist)
+	if err != nil {
+		return models.Song{}, err
+	}
+	song := models.Song{
+		ID:          id,
+		ArtistID:    artist.ID,
+		Song:        snapshot.Song,
+		ReleaseDate: snapshot.ReleaseDate,
+		Text:        snapshot.Text,
+		Link:        snapshot.Link,
+		Lang:        snapshot.Lang,
+		CreatedAt:   snapshot.CreatedAt,
+	}
+	if err := db.Omit("Artist", "Links", "Credits", "Genres", "Tags").Create(&song).Error; err != nil {
+		return models.Song{}, err
+	}
+
+	if len(snapshot.Links) > 0 {
+		links := make([]models.SongLink, len(snapshot.Links))
+		for i, link := range snapshot.Links {
+			links[i] = models.SongLink{SongID: id, Platform: link.Platform, Kind: link.Kind, URL: link.URL, CreatedAt: link.CreatedAt}
+		}
+		if err := db.Create(&links).Error; err != nil {
+			return models.Song{}, err
+		}
+	}
+
+	for _, credit := range snapshot.Credits {
+		creditArtist, err := restoreArtist(db, credit.Artist)
+		if err != nil {
+			return models.Song{}, err
+		}
+		// Если участники слились в одного артиста, повтор его роли пропускается.
+		restored := models.SongCredit{SongID: id, ArtistID: creditArtist.ID, Role: credit.Role, Position: credit.Position}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Omit("Artist").Create(&restored).Error; err != nil {
+			return models.Song{}, err
+		}
+	}
+
+	genreIDs := make([]uint, len(snapshot.Genres))
+	for i, genre := range snapshot.Genres {
+		genreIDs[i] = genre.ID
+	}
+	if err := attachExisting(db, &song, "Genres", &[]models.Genre{}, genreIDs); err != nil {
+		return models.Song{}, err
+	}
+	tagIDs := make([]uint, len(snapshot.Tags))
+	for i, tag := range snapshot.Tags {
+		tagIDs[i] = tag.ID
+	}
+	if err := attachExisting(db, &song, "Tags", &[]models.Tag{}, tagIDs); err != nil {
+		return models.Song{}, err
+	}
+
+	if err := restoreSongArchive(db, id); err != nil {
+		return models.Song{}, err
+	}
+
+	if err := PublishSongEvent(db, EventSongCreated, id); err != nil {
+		return models.Song{}, err
+	}
+	return findSongSnapshot(db, id)
+}
+
+// archiveSong сохраняет в song_archives данные песни, которые удалит каскад.
+// Повторное удаление восстановленной песни заменяет прежний архив.
+func archiveSong(db *gorm.DB, id uint) error {
+	var data models.SongArchiveData
+	if err := db.Where("song_id = ?", id).Order("lang").Find(&data.Translations).Error; err != nil {
+		return err
+	}
+	var synced []models.SyncedLyrics
+	if err := db.Where("song_id = ?", id).Limit(1).Find(&synced).Error; err != nil {
+		return err
+	}
+	if len(synced) > 0 {
+		data.SyncedLyrics = &synced[0]
+	}
+	var tracks []models.AlbumTrack
+	if err := db.Where("song_id = ?", id).Order("album_id, position").Find(&tracks).Error; err != nil {
+		return err
+	}
+	for _, track := range tracks {
+		data.AlbumTracks = append(data.AlbumTracks, models.ArchivedAlbumTrack{AlbumID: track.AlbumID, Position: track.Position})
+	}
+	var items []models.PlaylistItem
+	if err := db.Where("song_id = ?", id).Order("id").Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		data.PlaylistItems = append(data.PlaylistItems, models.ArchivedPlaylistItem{
+			ID: item.ID, PlaylistID: item.PlaylistID, Position: item.Position, AddedAt: item.AddedAt,
+		})
+	}
+	if err := db.Where("song_id = ?", id).Order("user_id").Find(&data.Favorites).Error; err != nil {
+		return err
+	}
+	var stats []models.SongStats
+	if err := db.Where("song_id = ?", id).Limit(1).Find(&stats).Error; err != nil {
+		return err
+	}
+	if len(stats) > 0 {
+		data.Stats = &models.ArchivedSongStats{
+			ListenCount:       stats[0].ListenCount,
+			ViewCount:         stats[0].ViewCount,
+			FavoriteCount:     stats[0].FavoriteCount,
+			TrendingScore:     stats[0].TrendingScore,
+			TrendingUpdatedAt: stats[0].TrendingUpdatedAt,
+		}
+	}
+
+	archive := models.SongArchive{SongID: id, Data: data, DeletedAt: time.Now()}
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&archive).Error
+}
+
+// restoreSongArchive возвращает восстановленной песне данные из song_archives и
+// удаляет архив. Места в удалённых с тех пор альбомах и плейлистах пропускаются,
+// а занятая другой песней позиция в альбоме заменяется концом треклиста.
+// Прослушивания, накопленные в буфере счётчиков в момент удаления, не сохраняются.
+// Для песен, удалённых до появления архива, ничего не делает.
+func restoreSongArchive(db *gorm.DB, id uint) error {
+	var archive models.SongArchive
+	err := db.Where("song_id = ?", id).First(&archive).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Log.Infof("Архив связанных данных песни %d не найден", id)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data := archive.Data
+
+	for i := range data.Translations {
+		data.Translations[i].ID = 0
+		data.Translations[i].SongID = id
+	}
+	if len(data.Translations) > 0 {
+		if err := db.Omit("Song").Create(&data.Translations).Error; err != nil {
+			return err
+		}
+	}
+	if data.SyncedLyrics != nil {
+		data.SyncedLyrics.ID = 0
+		data.SyncedLyrics.SongID = id
+		if err := db.Omit("Song").Create(data.SyncedLyrics).Error; err != nil {
+			return err
+		}
+	}
+	for _, track := range data.AlbumTracks {
+		err := db.Exec(`INSERT INTO album_tracks (album_id, position, song_id)
+			SELECT ?, CASE WHEN EXISTS (SELECT 1 FROM album_tracks WHERE album_id = ? AND position = ?)
+				THEN (SELECT MAX(position) + 1 FROM album_tracks WHERE album_id = ?) ELSE ? END, ?
+			WHERE EXISTS (SELECT 1 FROM albums WHERE id = ?)`,
+			track.AlbumID, track.AlbumID, track.Position, track.AlbumID, track.Position, id, track.AlbumID).Error
+		if err != nil {
+			return err
+		}
+	}
+	for _, item := range data.PlaylistItems {
+		err := db.Exec(`INSERT INTO playlist_items (id, playlist_id, position, song_id, added_at)
+			SELECT ?, ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM playlists WHERE id = ?)`,
+			item.ID, item.PlaylistID, item.Position, id, item.AddedAt, item.PlaylistID).Error
+		if err != nil {
+			return err
+		}
+	}
+	for i := range data.Favorites {
+		data.Favorites[i].SongID = id
+	}
+	if len(data.Favorites) > 0 {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Omit("Song").Create(&data.Favorites).Error; err != nil {
+			return err
+		}
+	}
+	if data.Stats != nil {
+		stats := models.SongStats{
+			SongID:            id,
+			ListenCount:       data.Stats.ListenCount,
+			ViewCount:         data.Stats.ViewCount,
+			FavoriteCount:     data.Stats.FavoriteCount,
+			TrendingScore:     data.Stats.TrendingScore,
+			TrendingUpdatedAt: data.Stats.TrendingUpdatedAt,
+		}
+		if err := db.Omit("Song").Create(&stats).Error; err != nil {
+			return err
+		}
+	}
+	return db.Delete(&archive).Error
+}
+
+// restoreArtist возвращает артиста из снимка, если он ещё существует, иначе
+// находит его по имени или псевдониму либо создаёт.
+func restoreArtist(db *gorm.DB, artist models.Artist) (models.Artist, error) {
+	var existing models.Artist
+	err := db.First(&existing, artist.ID).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return existing, err
+	}
+	return ResolveArtist(db, artist.Name)
+}
+
+// attachExisting привязывает к песне жанры или теги с указанными ID, которые
+// ещё существуют; found — указатель на срез моделей ассоциации.
+func attachExisting(db *gorm.DB, song *models.Song, association string, found interface{}, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := db.Where("id IN ?", ids).Find(found)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return db.Model(song).Association(association).Append(found)
+}
+
+// EnrichSong заново запрашивает данные песни во внешнем API: дата релиза, текст
+// и ссылка заменяются полученными (пустые значения не затирают текущие),
+// дополнительные ссылки добавляются к песне.
+func EnrichSong(db *gorm.DB, id uint, detail *models.SongDetail) (models.Song, error) {
+	if err := db.Select("id").First(&models.Song{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Song{}, ErrSongNotFound
+		}
+		return models.Song{}, err
+	}
+	if _, err := AddSongLinks(db, id, detail.Links...); err != nil {
+		return models.Song{}, err
+	}
+
+	var update models.SongUpdate
+	if date, err := ParseReleaseDate(detail.ReleaseDate); err != nil {
+		logger.Log.Errorf("ошибка парсинга даты: %v", err)
+	} else if date != nil {
+		update.ReleaseDate = date
+	}
+	if detail.Text != "" {
+		update.Text = &detail.Text
+	}
+	if detail.Link != "" {
+		update.Link = &detail.Link
+	}
+	return UpdateSong(db, id, update)
+}
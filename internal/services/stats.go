@@ -0,0 +1,287 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PlayKindListen = "listen"
+	PlayKindView   = "view"
+
+	// Вклад событий в популярность и тренды. Просмотр клипа весит меньше
+	// прослушивания, добавление в избранное — больше.
+	listenWeight   = 1.0
+	viewWeight     = 0.25
+	favoriteWeight = 3.0
+
+	// За это время вклад события в тренды уменьшается вдвое.
+	trendingHalfLife = 72 * time.Hour
+	// Как часто буферизованные счётчики сбрасываются в song_stats.
+	statsFlushInterval = 30 * time.Second
+
+	listenCountersKey = "stats:listens"
+	viewCountersKey   = "stats:views"
+	trendCountersKey  = "stats:trend"
+)
+
+var (
+	// PopularitySQL — популярность песни за всё время по таблице song_stats
+	// с теми же весами событий, что и у трендов.
+	PopularitySQL = "(COALESCE(song_stats.listen_count, 0) * " + sqlWeight(listenWeight) +
+		" + COALESCE(song_stats.view_count, 0) * " + sqlWeight(viewWeight) +
+		" + COALESCE(song_stats.favorite_count, 0) * " + sqlWeight(favoriteWeight) + ")"
+
+	ErrInvalidPlayKind  = errors.New("Некорректный тип события, допустимы listen и view")
+	ErrFavoriteNotFound = errors.New("Песни нет в избранном")
+
+	// Коэффициент затухания трендов в секунду.
+	trendingDecay = math.Ln2 / trendingHalfLife.Seconds()
+)
+
+func sqlWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
+
+// trendingSQL — текущий трендовый рейтинг с учётом затухания с момента последнего сброса.
+func trendingSQL() string {
+	return "(song_stats.trending_score * EXP(-" + strconv.FormatFloat(trendingDecay, 'g', -1, 64) +
+		" * EXTRACT(EPOCH FROM (NOW() - song_stats.trending_updated_at))))"
+}
+
+// RecordPlay учитывает прослушивание или просмотр песни. Счётчик копится в Redis
+// (или в памяти, если Redis недоступен) и попадает в БД при следующем сбросе.
+func RecordPlay(db *gorm.DB, songID uint, kind string) error {
+	if kind == "" {
+		kind = PlayKindListen
+	}
+	key, weight := listenCountersKey, listenWeight
+	switch kind {
+	case PlayKindListen:
+	case PlayKindView:
+		key, weight = viewCountersKey, viewWeight
+	default:
+		return ErrInvalidPlayKind
+	}
+	if err := db.Select("id").First(&models.Song{}, songID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSongNotFound
+		}
+		return err
+	}
+
+	field := strconv.FormatUint(uint64(songID), 10)
+	cache.IncrCounter(key, field, 1)
+	cache.IncrCounter(trendCountersKey, field, weight)
+	return nil
+}
+
+// AddFavorite добавляет песню в избранное пользователя и сообщает, была ли она
+// добавлена сейчас. Повторное добавление ничего не меняет. Вклад в тренды
+// учитывается отдельно, после фиксации транзакции, через RecordFavoriteTrend.
+func AddFavorite(db *gorm.DB, userID string, songID uint) (bool, error) {
+	if userID == "" {
+		return false, ErrOwnerRequired
+	}
+	if err := db.Select("id").First(&models.Song{}, songID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrSongNotFound
+		}
+		return false, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Favorite{UserID: userID, SongID: songID})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	if err := changeFavoriteCount(db, songID, 1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordFavoriteTrend учитывает добавление в избранное в трендах. Счётчик
+// живёт вне БД и не откатывается, поэтому вызывается только после фиксации.
+func RecordFavoriteTrend(songID uint) {
+	cache.IncrCounter(trendCountersKey, strconv.FormatUint(uint64(songID), 10), favoriteWeight)
+}
+
+// RemoveFavorite убирает песню из избранного пользователя.
+func RemoveFavorite(db *gorm.DB, userID string, songID uint) error {
+	if userID == "" {
+		return ErrOwnerRequired
+	}
+	result := db.Where("user_id = ? AND song_id = ?", userID, songID).Delete(&models.Favorite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFavoriteNotFound
+	}
+	return changeFavoriteCount(db, songID, -1)
+}
+
+// ListFavorites возвращает избранные песни пользователя, недавно добавленные первыми.
+func ListFavorites(db *gorm.DB, userID string) ([]models.Favorite, error) {
+	if userID == "" {
+		return nil, ErrOwnerRequired
+	}
+	favorites := []models.Favorite{}
+	err := db.Preload("Song").Preload("Song.Artist").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&favorites).Error
+	return favorites, err
+}
+
+// FindSongStats возвращает счётчики песни из БД. События, ещё не сброшенные
+// из буфера, не учитываются.
+func FindSongStats(db *gorm.DB, songID uint) (models.SongStats, error) {
+	stats := models.SongStats{SongID: songID}
+	if err := db.Select("id").First(&models.Song{}, songID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return stats, ErrSongNotFound
+		}
+		return stats, err
+	}
+	err := db.Where("song_id = ?", songID).Limit(1).Find(&stats).Error
+	return stats, err
+}
+
+// PopularSongs возвращает самые популярные песни за всё время.
+func PopularSongs(db *gorm.DB, limit int) ([]models.SongRanking, error) {
+	return rankSongs(db, PopularitySQL, limit)
+}
+
+// TrendingSongs возвращает песни, популярные в последнее время: вклад каждого
+// события затухает вдвое за trendingHalfLife.
+func TrendingSongs(db *gorm.DB, limit int) ([]models.SongRanking, error) {
+	return rankSongs(db, trendingSQL(), limit)
+}
+
+func rankSongs(db *gorm.DB, scoreSQL string, limit int) ([]models.SongRanking, error) {
+	type scoredStats struct {
+		models.SongStats
+		Score float64
+	}
+	var scored []scoredStats
+	err := db.Table("song_stats").
+		Select("song_stats.*, " + scoreSQL + " AS score").
+		Where(scoreSQL + " > 0").
+		Order("score DESC, song_stats.song_id").
+		Limit(limit).
+		Scan(&scored).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(scored))
+	for i, s := range scored {
+		ids[i] = s.SongID
+	}
+	var songs []models.Song
+	if err := db.Preload("Artist").Where("id IN ?", ids).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Song, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+	}
+
+	rankings := make([]models.SongRanking, 0, len(scored))
+	for _, s := range scored {
+		song, ok := byID[s.SongID]
+		if !ok {
+			continue
+		}
+		rankings = append(rankings, models.SongRanking{
+			Rank:  len(rankings) + 1,
+			Score: math.Round(s.Score*1000) / 1000,
+			Song:  song,
+			Stats: s.SongStats,
+		})
+	}
+	return rankings, nil
+}
+
+// FlushSongStats переносит накопленные счётчики в таблицу song_stats. Если запись
+// не удалась, счётчики возвращаются в буфер до следующей попытки.
+func FlushSongStats(db *gorm.DB) error {
+	listens := cache.DrainCounters(listenCountersKey)
+	views := cache.DrainCounters(viewCountersKey)
+	trend := cache.DrainCounters(trendCountersKey)
+	if len(listens) == 0 && len(views) == 0 && len(trend) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]bool)
+	for _, counters := range []map[string]float64{listens, views, trend} {
+		for field := range counters {
+			fields[field] = true
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for field := range fields {
+			songID, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				continue
+			}
+			// Песня могла быть удалена, пока счётчик лежал в буфере, — такие пропускаем.
+			err = tx.Exec(`INSERT INTO song_stats (song_id, listen_count, view_count, favorite_count, trending_score, trending_updated_at)
+				SELECT ?, ?, ?, 0, ?, NOW() WHERE EXISTS (SELECT 1 FROM songs WHERE id = ?)
+				ON CONFLICT (song_id) DO UPDATE SET
+					listen_count = song_stats.listen_count + EXCLUDED.listen_count,
+					view_count = song_stats.view_count + EXCLUDED.view_count,
+					trending_score = `+trendingSQL()+` + EXCLUDED.trending_score,
+					trending_updated_at = NOW()`,
+				songID, int64(listens[field]), int64(views[field]), trend[field], songID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for key, counters := range map[string]map[string]float64{listenCountersKey: listens, viewCountersKey: views, trendCountersKey: trend} {
+			for field, delta := range counters {
+				cache.IncrCounter(key, field, delta)
+			}
+		}
+		return err
+	}
+	logger.Log.Debugf("Счётчики прослушиваний сброшены в БД, песен: %d", len(fields))
+	return nil
+}
+
+// StartStatsFlusher периодически сбрасывает счётчики прослушиваний в БД.
+func StartStatsFlusher(db *gorm.DB) {
+	go func() {
+		ticker := time.NewTicker(statsFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := FlushSongStats(db); err != nil {
+				logger.Log.Errorf("Ошибка сброса счётчиков прослушиваний: %v", err)
+			}
+		}
+	}()
+}
+
+// changeFavoriteCount меняет число добавлений в избранное сразу в БД: в отличие от
+// прослушиваний, это редкое событие.
+func changeFavoriteCount(db *gorm.DB, songID uint, delta int) error {
+	return db.Exec(`INSERT INTO song_stats (song_id, listen_count, view_count, favorite_count, trending_score, trending_updated_at)
+		VALUES (?, 0, 0, GREATEST(?, 0), 0, NOW())
+		ON CONFLICT (song_id) DO UPDATE SET favorite_count = GREATEST(song_stats.favorite_count + ?, 0)`,
+		songID, delta, delta).Error
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"songs/internal/cache"
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Канал Redis, через который события расходятся по всем экземплярам сервиса.
+	eventsChannel = "catalog:events"
+	// Ключ с номером последнего опубликованного в канал события.
+	eventsCursorKey = "catalog:events:published"
+	// Как часто пересыльщик проверяет outbox на новые события.
+	eventsRelayInterval = 500 * time.Millisecond
+	eventsRelayBatch    = 500
+	// Сколько событий может ждать отправки одному подписчику. Если клиент читает
+	// медленнее, его поток закрывается и он переподключается с Last-Event-ID.
+	eventsSubscriberBuffer = 256
+)
+
+var ErrInvalidEventType = errors.New("Неизвестный тип события")
+
+// EventFilter отбирает события для потока: по типам (с масками вида song.*)
+// и по артисту.
+type EventFilter struct {
+	Types    []string
+	ArtistID uint
+}
+
+// NewEventFilter собирает фильтр потока, проверяя типы событий и маски.
+func NewEventFilter(types []string, artistID uint) (EventFilter, error) {
+	for _, pattern := range types {
+		if !validEventPattern(pattern) {
+			return EventFilter{}, fmt.Errorf("%w: %s", ErrInvalidEventType, pattern)
+		}
+	}
+	return EventFilter{Types: types, ArtistID: artistID}, nil
+}
+
+// Match проверяет, подходит ли событие под фильтр. Событие песни относится к
+// артисту, если он основной исполнитель или участник песни; artist.merged — и к
+// объединённому, и к поглощённому артисту.
+func (f EventFilter) Match(event models.Event) bool {
+	if !eventMatches(f.Types, event.Type) {
+		return false
+	}
+	if f.ArtistID == 0 {
+		return true
+	}
+	switch event.EntityType {
+	case "artist":
+		if event.EntityID == f.ArtistID {
+			return true
+		}
+		var merged artistMergedData
+		return event.Type == EventArtistMerged &&
+			json.Unmarshal(event.Data, &merged) == nil && merged.DuplicateID == f.ArtistID
+	case "song":
+		var song models.Song
+		if json.Unmarshal(event.Data, &song) != nil {
+			return false
+		}
+		if song.ArtistID == f.ArtistID {
+			return true
+		}
+		for _, credit := range song.Credits {
+			if credit.ArtistID == f.ArtistID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eventHub раздаёт события подписчикам этого экземпляра сервиса.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[chan models.Event]struct{}
+}
+
+var streamHub = &eventHub{subscribers: map[chan models.Event]struct{}{}}
+
+// SubscribeEvents подписывает на события каталога, фиксируемые с этого момента.
+// Канал закрывается, если подписчик не успевает читать события; cancel нужно
+// вызвать, когда события больше не нужны.
+func SubscribeEvents() (events <-chan models.Event, cancel func()) {
+	ch := make(chan models.Event, eventsSubscriberBuffer)
+	streamHub.mu.Lock()
+	streamHub.subscribers[ch] = struct{}{}
+	streamHub.mu.Unlock()
+	return ch, func() {
+		streamHub.mu.Lock()
+		defer streamHub.mu.Unlock()
+		if _, ok := streamHub.subscribers[ch]; ok {
+			delete(streamHub.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *eventHub) broadcast(event models.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(h.subscribers, ch)
+			close(ch)
+			logger.Log.Warn("Подписчик потока событий не успевает читать, поток закрыт")
+		}
+	}
+}
+
+// StartEventRelay пересылает новые события из outbox catalog_events подписчикам
+// потока. Каждый экземпляр сервиса читает outbox и публикует события в канал
+// Redis; Redis отбрасывает уже опубликованные номера, поэтому каждое событие
+// уходит в канал один раз, а подписка на канал доставляет его потокам всех
+// экземпляров. Если Redis недоступен, события раздаются только своим подписчикам.
+func StartEventRelay(db *gorm.DB) {
+	subscribed := cache.Subscribe(context.Background(), eventsChannel, func(payload []byte) {
+		var event models.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			logger.Log.Errorf("Некорректное событие в канале %s: %v", eventsChannel, err)
+			return
+		}
+		streamHub.broadcast(event)
+	})
+
+	var cursor uint64
+	if err := db.Model(&models.Event{}).Select("COALESCE(MAX(seq), 0)").Scan(&cursor).Error; err != nil {
+		logger.Log.Errorf("Ошибка чтения номера последнего события: %v", err)
+	}
+	go func() {
+		ticker := time.NewTicker(eventsRelayInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			changes, err := ListChanges(db, cursor, eventsRelayBatch)
+			if err != nil {
+				logger.Log.Errorf("Ошибка чтения событий для потока: %v", err)
+				continue
+			}
+			for _, event := range changes.Events {
+				relayEvent(event, subscribed)
+			}
+			cursor = changes.NextSince
+		}
+	}()
+}
+
+func relayEvent(event models.Event, subscribed bool) {
+	if subscribed {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			logger.Log.Errorf("Ошибка сериализации события %d: %v", event.Seq, err)
+			return
+		}
+		if _, err := cache.PublishOnce(eventsChannel, eventsCursorKey, event.Seq, payload); err == nil {
+			return
+		}
+	}
+	streamHub.broadcast(event)
+}
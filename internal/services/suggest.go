@@ -0,0 +1,263 @@
+package services
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+	"songs/internal/search"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SuggestionTypeArtist = "artist"
+	SuggestionTypeSong   = "song"
+
+	// Размер выдачи автодополнения фиксирован: строке поиска больше не нужно.
+	SuggestionLimit = 10
+	// Полная пересборка индекса подхватывает изменения, сделанные в обход
+	// обработчиков (импорт, другие экземпляры сервиса), и свежую популярность.
+	suggestionRefreshInterval = 5 * time.Minute
+)
+
+// Индекс автодополнения целиком в памяти: поиск по нему не ходит ни в БД, ни в Redis.
+// При пересборке новый индекс подменяет старый целиком.
+var suggestionIndex atomic.Pointer[search.PrefixIndex[models.Suggestion]]
+
+var (
+	// rebuildMu не даёт двум пересборкам идти одновременно.
+	rebuildMu sync.Mutex
+	// suggestionMu защищает pendingSuggestions и подмену индекса.
+	suggestionMu sync.Mutex
+	// pendingSuggestions — изменения, сделанные во время пересборки. Новый индекс
+	// строится по снимку БД, который мог их не застать, поэтому после подмены
+	// они применяются к нему повторно. nil — пересборка не идёт.
+	pendingSuggestions *suggestionChanges
+)
+
+type suggestionChanges struct {
+	songIDs          []uint
+	removedArtistIDs []uint
+}
+
+func init() {
+	suggestionIndex.Store(search.NewPrefixIndex[models.Suggestion]())
+}
+
+type songSuggestionRow struct {
+	ID         uint
+	Song       string
+	ArtistID   uint
+	Artist     string
+	Popularity float64
+}
+
+type artistSuggestionRow struct {
+	ID     uint
+	Name   string
+	Weight float64
+}
+
+// Suggest возвращает артистов и песни, название которых или одно из слов в нём
+// начинается с запроса. Популярные варианты выше.
+func Suggest(query string) []models.Suggestion {
+	suggestions := suggestionIndex.Load().Search(query, SuggestionLimit)
+	if suggestions == nil {
+		suggestions = []models.Suggestion{}
+	}
+	return suggestions
+}
+
+// RebuildSuggestions заново строит индекс автодополнения по всему каталогу.
+// Изменения, сделанные во время пересборки, не теряются: после подмены индекса
+// они применяются к нему ещё раз.
+func RebuildSuggestions(db *gorm.DB) error {
+	rebuildMu.Lock()
+	defer rebuildMu.Unlock()
+
+	changes := &suggestionChanges{}
+	suggestionMu.Lock()
+	pendingSuggestions = changes
+	suggestionMu.Unlock()
+
+	index, artists, songs, err := buildSuggestions(db)
+
+	suggestionMu.Lock()
+	if err == nil {
+		suggestionIndex.Store(index)
+	}
+	pendingSuggestions = nil
+	suggestionMu.Unlock()
+	if err != nil {
+		return err
+	}
+	logger.Log.Debugf("Индекс автодополнения перестроен: артистов %d, песен %d", artists, songs)
+
+	for _, id := range changes.removedArtistIDs {
+		RemoveArtistSuggestion(id)
+	}
+	return UpdateSongSuggestions(db, changes.songIDs...)
+}
+
+func buildSuggestions(db *gorm.DB) (*search.PrefixIndex[models.Suggestion], int, int, error) {
+	artists, err := loadArtistSuggestions(db, nil)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	songs, err := loadSongSuggestions(db, nil)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	entries := make([]search.PrefixEntry[models.Suggestion], 0, len(artists)+len(songs))
+	for _, artist := range artists {
+		entries = append(entries, artistSuggestionEntry(artist))
+	}
+	for _, song := range songs {
+		entries = append(entries, songSuggestionEntry(song))
+	}
+	return search.NewPrefixIndexFrom(entries), len(artists), len(songs), nil
+}
+
+// UpdateSongSuggestions обновляет в индексе автодополнения перечисленные песни
+// и их участников. Песни, которых больше нет в БД, удаляются из индекса.
+func UpdateSongSuggestions(db *gorm.DB, songIDs ...uint) error {
+	if len(songIDs) == 0 {
+		return nil
+	}
+	suggestionMu.Lock()
+	if pendingSuggestions != nil {
+		pendingSuggestions.songIDs = append(pendingSuggestions.songIDs, songIDs...)
+	}
+	suggestionMu.Unlock()
+
+	songs, err := loadSongSuggestions(db, songIDs)
+	if err != nil {
+		return err
+	}
+	var artistIDs []uint
+	if err := db.Model(&models.SongCredit{}).Where("song_id IN ?", songIDs).
+		Distinct().Pluck("artist_id", &artistIDs).Error; err != nil {
+		return err
+	}
+	for _, song := range songs {
+		artistIDs = append(artistIDs, song.ArtistID)
+	}
+	var artists []artistSuggestionRow
+	if len(artistIDs) > 0 {
+		if artists, err = loadArtistSuggestions(db, artistIDs); err != nil {
+			return err
+		}
+	}
+
+	index := suggestionIndex.Load()
+	found := make(map[uint]bool, len(songs))
+	for _, song := range songs {
+		putSuggestion(index, songSuggestionEntry(song))
+		found[song.ID] = true
+	}
+	for _, id := range songIDs {
+		if !found[id] {
+			index.Remove(suggestionID(SuggestionTypeSong, id))
+		}
+	}
+	for _, artist := range artists {
+		putSuggestion(index, artistSuggestionEntry(artist))
+	}
+	return nil
+}
+
+// RemoveArtistSuggestion убирает удалённого артиста из индекса автодополнения.
+func RemoveArtistSuggestion(artistID uint) {
+	suggestionMu.Lock()
+	if pendingSuggestions != nil {
+		pendingSuggestions.removedArtistIDs = append(pendingSuggestions.removedArtistIDs, artistID)
+	}
+	suggestionMu.Unlock()
+	suggestionIndex.Load().Remove(suggestionID(SuggestionTypeArtist, artistID))
+}
+
+// StartSuggestionsRefresher строит индекс автодополнения и периодически перестраивает его.
+func StartSuggestionsRefresher(db *gorm.DB) {
+	if err := RebuildSuggestions(db); err != nil {
+		logger.Log.Errorf("Ошибка построения индекса автодополнения: %v", err)
+	}
+	go func() {
+		ticker := time.NewTicker(suggestionRefreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := RebuildSuggestions(db); err != nil {
+				logger.Log.Errorf("Ошибка построения индекса автодополнения: %v", err)
+			}
+		}
+	}()
+}
+
+// loadSongSuggestions читает песни для индекса; ids == nil — все песни.
+func loadSongSuggestions(db *gorm.DB, ids []uint) ([]songSuggestionRow, error) {
+	query := db.Table("songs").
+		Select("songs.id, songs.song, songs.artist_id, artists.name AS artist, " + PopularitySQL + " AS popularity").
+		Joins("JOIN artists ON artists.id = songs.artist_id").
+		Joins("LEFT JOIN song_stats ON song_stats.song_id = songs.id")
+	if ids != nil {
+		query = query.Where("songs.id IN ?", ids)
+	}
+	var rows []songSuggestionRow
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
+// loadArtistSuggestions читает артистов для индекса; вес артиста — число его
+// песен плюс их суммарная популярность. ids == nil — все артисты.
+func loadArtistSuggestions(db *gorm.DB, ids []uint) ([]artistSuggestionRow, error) {
+	query := db.Table("artists").
+		Select("artists.id, artists.name, COUNT(songs.id) + COALESCE(SUM(" + PopularitySQL + "), 0) AS weight").
+		Joins("LEFT JOIN songs ON songs.artist_id = artists.id").
+		Joins("LEFT JOIN song_stats ON song_stats.song_id = songs.id").
+		Group("artists.id")
+	if ids != nil {
+		query = query.Where("artists.id IN ?", ids)
+	}
+	var rows []artistSuggestionRow
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
+func putSuggestion(index *search.PrefixIndex[models.Suggestion], entry search.PrefixEntry[models.Suggestion]) {
+	index.Put(entry.ID, entry.Name, entry.Weight, entry.Value)
+}
+
+func songSuggestionEntry(song songSuggestionRow) search.PrefixEntry[models.Suggestion] {
+	return search.PrefixEntry[models.Suggestion]{
+		ID:     suggestionID(SuggestionTypeSong, song.ID),
+		Name:   song.Song,
+		Weight: song.Popularity,
+		Value: models.Suggestion{
+			Type:     SuggestionTypeSong,
+			ID:       song.ID,
+			Name:     song.Song,
+			Artist:   song.Artist,
+			ArtistID: song.ArtistID,
+		},
+	}
+}
+
+func artistSuggestionEntry(artist artistSuggestionRow) search.PrefixEntry[models.Suggestion] {
+	return search.PrefixEntry[models.Suggestion]{
+		ID:     suggestionID(SuggestionTypeArtist, artist.ID),
+		Name:   artist.Name,
+		Weight: artist.Weight,
+		Value: models.Suggestion{
+			Type: SuggestionTypeArtist,
+			ID:   artist.ID,
+			Name: artist.Name,
+		},
+	}
+}
+
+func suggestionID(kind string, id uint) string {
+	return kind + ":" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxTagLength = 64
+
+var (
+	ErrGenreNotFound = errors.New("Жанр не найден")
+	ErrInvalidGenre  = errors.New("Название жанра не может быть пустым")
+	ErrGenreExists   = errors.New("Жанр с таким названием уже существует")
+	ErrGenreCycle    = errors.New("Жанр не может быть вложен сам в себя или в свой поджанр")
+	ErrInvalidGenres = errors.New("Список жанров содержит несуществующие жанры")
+	ErrInvalidTag    = errors.New("Тег не может быть пустым или длиннее 64 символов")
+)
+
+// GenreSubtreeSQL — подзапрос с ID жанров с указанными названиями (в нижнем регистре)
+// и всех их поджанров. Фильтр по "rock" находит и песни в жанре "alternative rock",
+// если он вложен в "rock".
+const GenreSubtreeSQL = `WITH RECURSIVE subtree AS (
+	SELECT id FROM genres WHERE LOWER(name) IN ?
+	UNION SELECT genres.id FROM genres JOIN subtree ON genres.parent_id = subtree.id
+) SELECT id FROM subtree`
+
+// ListGenres возвращает все жанры по алфавиту. Если tree=true, жанры собираются
+// в дерево: в корне только жанры без родителя, поджанры в поле children.
+func ListGenres(db *gorm.DB, tree bool) ([]models.Genre, error) {
+	genres := []models.Genre{}
+	if err := db.Order("name").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+	if !tree {
+		return genres, nil
+	}
+
+	children := make(map[uint][]models.Genre)
+	for _, genre := range genres {
+		if genre.ParentID != nil {
+			children[*genre.ParentID] = append(children[*genre.ParentID], genre)
+		}
+	}
+	var attach func(genre models.Genre) models.Genre
+	attach = func(genre models.Genre) models.Genre {
+		for _, child := range children[genre.ID] {
+			genre.Children = append(genre.Children, attach(child))
+		}
+		return genre
+	}
+	roots := []models.Genre{}
+	for _, genre := range genres {
+		if genre.ParentID == nil {
+			roots = append(roots, attach(genre))
+		}
+	}
+	return roots, nil
+}
+
+// CreateGenre создаёт жанр, при необходимости вложенный в родительский.
+func CreateGenre(db *gorm.DB, input models.GenreInput) (models.Genre, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return models.Genre{}, ErrInvalidGenre
+	}
+	if err := checkGenreName(db, name, 0); err != nil {
+		return models.Genre{}, err
+	}
+
+	genre := models.Genre{Name: name}
+	if input.ParentID != nil && *input.ParentID != 0 {
+		if err := db.Select("id").First(&models.Genre{}, *input.ParentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return genre, ErrGenreNotFound
+			}
+			return genre, err
+		}
+		genre.ParentID = input.ParentID
+	}
+	if err := db.Omit("Parent").Create(&genre).Error; err != nil {
+		return genre, err
+	}
+	return genre, nil
+}
+
+// UpdateGenre переименовывает жанр или переносит его к другому родителю.
+// Возвращает ID песен этого жанра, чтобы вызывающий сбросил их кеш.
+func UpdateGenre(db *gorm.DB, id uint, input models.GenreUpdate) (models.Genre, []uint, error) {
+	var genre models.Genre
+	if err := db.First(&genre, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return genre, nil, ErrGenreNotFound
+		}
+		return genre, nil, err
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return genre, nil, ErrInvalidGenre
+		}
+		if err := checkGenreName(db, name, genre.ID); err != nil {
+			return genre, nil, err
+		}
+		genre.Name = name
+	}
+	if input.ParentID != nil {
+		if *input.ParentID == 0 {
+			genre.ParentID = nil
+		} else {
+			if err := checkGenreParent(db, genre.ID, *input.ParentID); err != nil {
+				return genre, nil, err
+			}
+			genre.ParentID = input.ParentID
+		}
+	}
+
+	if err := db.Omit("Parent").Save(&genre).Error; err != nil {
+		return genre, nil, err
+	}
+	songIDs, err := genreSongIDs(db, genre.ID)
+	return genre, songIDs, err
+}
+
+// DeleteGenre удаляет жанр. Поджанры становятся корневыми, у песен жанр снимается.
+// Возвращает ID песен, которые были в этом жанре.
+func DeleteGenre(db *gorm.DB, id uint) ([]uint, error) {
+	songIDs, err := genreSongIDs(db, id)
+	if err != nil {
+		return nil, err
+	}
+	result := db.Delete(&models.Genre{}, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrGenreNotFound
+	}
+	return songIDs, nil
+}
+
+// SetSongGenres заменяет жанры песни.
+func SetSongGenres(db *gorm.DB, songID uint, genreIDs []uint) ([]models.Genre, error) {
+	song, err := findSongForTaxonomy(db, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := []models.Genre{}
+	if len(genreIDs) > 0 {
+		if err := db.Where("id IN ?", genreIDs).Order("name").Find(&genres).Error; err != nil {
+			return nil, err
+		}
+		if len(genres) != len(uniqueIDs(genreIDs)) {
+			return nil, ErrInvalidGenres
+		}
+	}
+	ids := make([]uint, len(genres))
+	for i, genre := range genres {
+		ids[i] = genre.ID
+	}
+	if err := replaceSongJoinRows(db, "song_genres", "genre_id", song.ID, ids); err != nil {
+		return nil, err
+	}
+	return genres, PublishSongEvent(db, EventSongUpdated, song.ID)
+}
+
+// SetSongTags заменяет теги песни. Теги приводятся к нижнему регистру,
+// новые создаются автоматически.
+func SetSongTags(db *gorm.DB, songID uint, names []string) ([]models.Tag, error) {
+	song, err := findSongForTaxonomy(db, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		tag, err := NormalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	tags := []models.Tag{}
+	if len(normalized) > 0 {
+		create := make([]models.Tag, len(normalized))
+		for i, name := range normalized {
+			create[i] = models.Tag{Name: name}
+		}
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoNothing: true,
+		}).Create(&create).Error
+		if err != nil {
+			return nil, err
+		}
+		if err := db.Where("name IN ?", normalized).Order("name").Find(&tags).Error; err != nil {
+			return nil, err
+		}
+	}
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	if err := replaceSongJoinRows(db, "song_tags", "tag_id", song.ID, ids); err != nil {
+		return nil, err
+	}
+	return tags, PublishSongEvent(db, EventSongUpdated, song.ID)
+}
+
+// ListTags возвращает теги с количеством песен, самые популярные первыми.
+func ListTags(db *gorm.DB) ([]models.FacetCount, error) {
+	tags := []models.FacetCount{}
+	err := db.Table("tags").
+		Select("tags.id, tags.name, COUNT(song_tags.song_id) AS count").
+		Joins("LEFT JOIN song_tags ON song_tags.tag_id = tags.id").
+		Group("tags.id, tags.name").
+		Order("count DESC, tags.name").
+		Scan(&tags).Error
+	return tags, err
+}
+
+// NormalizeTag приводит тег к нижнему регистру и схлопывает пробелы.
+func NormalizeTag(name string) (string, error) {
+	tag := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if tag == "" || len([]rune(tag)) > maxTagLength {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
+
+func checkGenreName(db *gorm.DB, name string, exceptID uint) error {
+	var count int64
+	err := db.Model(&models.Genre{}).Where("LOWER(name) = LOWER(?) AND id <> ?", name, exceptID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrGenreExists
+	}
+	return nil
+}
+
+// checkGenreParent проверяет, что родитель существует и не является самим жанром
+// или его потомком.
+func checkGenreParent(db *gorm.DB, genreID, parentID uint) error {
+	if err := db.Select("id").First(&models.Genre{}, parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGenreNotFound
+		}
+		return err
+	}
+
+	var descendants []uint
+	err := db.Raw(`WITH RECURSIVE subtree AS (
+		SELECT id FROM genres WHERE id = ?
+		UNION SELECT genres.id FROM genres JOIN subtree ON genres.parent_id = subtree.id
+	) SELECT id FROM subtree`, genreID).Scan(&descendants).Error
+	if err != nil {
+		return err
+	}
+	for _, id := range descendants {
+		if id == parentID {
+			return ErrGenreCycle
+		}
+	}
+	return nil
+}
+
+func genreSongIDs(db *gorm.DB, genreID uint) ([]uint, error) {
+	var songIDs []uint
+	err := db.Table("song_genres").Where("genre_id = ?", genreID).Pluck("song_id", &songIDs).Error
+	return songIDs, err
+}
+
+func findSongForTaxonomy(db *gorm.DB, songID uint) (models.Song, error) {
+	var song models.Song
+	if err := db.Select("id").First(&song, songID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return song, ErrSongNotFound
+		}
+		return song, err
+	}
+	return song, nil
+}
+
+// replaceSongJoinRows заменяет строки связующей таблицы песни (song_genres, song_tags).
+func replaceSongJoinRows(db *gorm.DB, table, column string, songID uint, ids []uint) error {
+	if err := db.Exec("DELETE FROM "+table+" WHERE song_id = ?", songID).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	rows := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		rows[i] = map[string]interface{}{"song_id": songID, column: id}
+	}
+	return db.Table(table).Create(&rows).Error
+}
+
+func uniqueIDs(ids []uint) map[uint]bool {
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	return unique
+}
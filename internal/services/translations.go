@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidLang         = errors.New("Некорректный код языка, ожидается код вида en или pt-BR")
+	ErrTranslationNotFound = errors.New("Перевод не найден")
+
+	langPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+)
+
+// NormalizeLang приводит код языка к нижнему регистру (en_US -> en-us) и проверяет его формат.
+func NormalizeLang(lang string) (string, error) {
+	lang = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+	if !langPattern.MatchString(lang) {
+		return "", ErrInvalidLang
+	}
+	return lang, nil
+}
+
+// PreferredLangs возвращает языки в порядке предпочтения клиента. Явно указанный
+// параметр lang важнее заголовка Accept-Language.
+func PreferredLangs(queryLang, acceptLanguage string) []string {
+	if lang, err := NormalizeLang(queryLang); err == nil {
+		return []string{lang}
+	}
+
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var prefs []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang, err := NormalizeLang(fields[0])
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, weighted{lang: lang, q: q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	langs := make([]string, len(prefs))
+	for i, p := range prefs {
+		langs[i] = p.lang
+	}
+	return langs
+}
+
+// SelectLang выбирает язык текста по предпочтениям клиента среди оригинала и доступных
+// переводов: сначала точное совпадение, затем по основному коду (en-us -> en).
+// Если ничего не подошло, возвращается язык оригинала.
+func SelectLang(prefs []string, original string, available []string) string {
+	candidates := append([]string{original}, available...)
+	for _, pref := range prefs {
+		for _, candidate := range candidates {
+			if candidate != "" && candidate == pref {
+				return candidate
+			}
+		}
+		for _, candidate := range candidates {
+			if candidate != "" && primaryLang(candidate) == primaryLang(pref) {
+				return candidate
+			}
+		}
+	}
+	return original
+}
+
+// FindTranslation подбирает перевод песни по предпочтениям клиента. Если лучше всего
+// подходит оригинал или переводов нет, ok будет false.
+func FindTranslation(db *gorm.DB, songID uint, original string, prefs []string) (models.SongTranslation, bool, error) {
+	var translation models.SongTranslation
+	if len(prefs) == 0 {
+		return translation, false, nil
+	}
+
+	var available []string
+	if err := db.Model(&models.SongTranslation{}).Where("song_id = ?", songID).Pluck("lang", &available).Error; err != nil {
+		return translation, false, err
+	}
+	if len(available) == 0 {
+		return translation, false, nil
+	}
+
+	lang := SelectLang(prefs, original, available)
+	if lang == "" || lang == original {
+		return translation, false, nil
+	}
+	if err := db.Where("song_id = ? AND lang = ?", songID, lang).First(&translation).Error; err != nil {
+		return translation, false, err
+	}
+	return translation, true, nil
+}
+
+// AlignSections сопоставляет секции оригинала и перевода по порядковому номеру
+// для вывода текста в две колонки.
+func AlignSections(original, translation []models.LyricsSection) []models.AlignedSection {
+	aligned := make([]models.AlignedSection, len(original))
+	for i, section := range original {
+		aligned[i] = models.AlignedSection{
+			Index:       section.Index,
+			Type:        section.Type,
+			Original:    section.Lines,
+			Translation: []string{},
+		}
+		if section.Index < len(translation) {
+			aligned[i].Translation = translation[section.Index].Lines
+		}
+	}
+	return aligned
+}
+
+// LangMatches сообщает, совпадают ли языки точно или по основному коду.
+func LangMatches(a, b string) bool {
+	return a == b || primaryLang(a) == primaryLang(b)
+}
+
+func primaryLang(lang string) string {
+	primary, _, _ := strings.Cut(lang, "-")
+	return primary
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"songs/internal/logger"
+	"songs/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	// Попытки исчерпаны: доставка остаётся в журнале недоставленных событий.
+	DeliveryStatusDead = "dead"
+
+	webhookMaxAttempts = 10
+	// Задержка перед повтором удваивается с каждой попыткой: 10 с, 20 с, 40 с… до часа.
+	webhookBaseBackoff = 10 * time.Second
+	webhookMaxBackoff  = time.Hour
+	webhookTimeout     = 10 * time.Second
+	// На время отправки доставка откладывается, чтобы другой экземпляр сервиса
+	// не взял её повторно; если процесс упадёт, она вернётся в очередь после аренды.
+	webhookLease        = time.Minute
+	webhookPollInterval = 2 * time.Second
+	webhookBatchSize    = 20
+	// Сколько байт ответа подписчика сохранять в lastError.
+	webhookErrorBodyLimit = 512
+	// Сколько хранить историю доставок: доставленные нужны для разбора недавних
+	// событий, недоставленные — дольше, чтобы успеть исправить подписчика и повторить.
+	webhookDeliveredRetention = 7 * 24 * time.Hour
+	webhookDeadRetention      = 30 * 24 * time.Hour
+	webhookPruneInterval      = time.Hour
+
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("Подписка не найдена")
+	ErrDeliveryNotFound = errors.New("Доставка не найдена")
+	ErrInvalidWebhook   = errors.New("Некорректная подписка: нужен URL http(s) на публичный адрес и известные типы событий")
+	ErrInvalidStatus    = errors.New("Некорректный статус доставки, допустимы pending, delivered, dead")
+
+	errPrivateAddress = errors.New("адрес подписчика во внутренней сети запрещён")
+
+	// Вебхуки отправляются только на публичные адреса: проверка в Control
+	// выполняется после разрешения имени для каждого соединения, включая
+	// перенаправления, поэтому её не обойти DNS-записью на внутренний адрес.
+	// Прокси из окружения не используется, иначе проверялся бы адрес прокси.
+	webhookClient = &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: webhookTimeout,
+				Control: rejectPrivateAddress,
+			}).DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+)
+
+// ListWebhooks возвращает подписки без секретов.
+func ListWebhooks(db *gorm.DB) ([]models.WebhookSubscription, error) {
+	subscriptions := []models.WebhookSubscription{}
+	if err := db.Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// FindWebhook возвращает подписку без секрета.
+func FindWebhook(db *gorm.DB, id uint) (models.WebhookSubscription, error) {
+	subscription, err := findWebhook(db, id)
+	subscription.Secret = ""
+	return subscription, err
+}
+
+// CreateWebhook создаёт подписку. Если секрет не передан, он генерируется;
+// в ответе секрет возвращается, чтобы подписчик мог проверять подписи.
+func CreateWebhook(db *gorm.DB, input models.WebhookInput) (models.WebhookSubscription, error) {
+	subscription := models.WebhookSubscription{
+		URL:    strings.TrimSpace(input.URL),
+		Events: models.StringList(normalizeEventPatterns(input.Events)),
+		Secret: input.Secret,
+		Active: true,
+	}
+	if err := validateWebhook(subscription); err != nil {
+		return subscription, err
+	}
+	if subscription.Secret == "" {
+		secret, err := newShareToken()
+		if err != nil {
+			return subscription, err
+		}
+		subscription.Secret = secret
+	}
+	if err := db.Create(&subscription).Error; err != nil {
+		return subscription, err
+	}
+	logger.Log.Infof("Создана подписка на вебхуки %d: %s", subscription.ID, subscription.URL)
+	return subscription, nil
+}
+
+// UpdateWebhook применяет к подписке переданные поля. Секрет возвращается, только если его сменили.
+func UpdateWebhook(db *gorm.DB, id uint, input models.WebhookUpdate) (models.WebhookSubscription, error) {
+	subscription, err := findWebhook(db, id)
+	if err != nil {
+		return subscription, err
+	}
+	if input.URL != nil {
+		subscription.URL = strings.TrimSpace(*input.URL)
+	}
+	if input.Events != nil {
+		subscription.Events = models.StringList(normalizeEventPatterns(*input.Events))
+	}
+	if input.Secret != nil {
+		if *input.Secret == "" {
+			return subscription, ErrInvalidWebhook
+		}
+		subscription.Secret = *input.Secret
+	}
+	if input.Active != nil {
+		subscription.Active = *input.Active
+	}
+	if err := validateWebhook(subscription); err != nil {
+		return subscription, err
+	}
+	if err := db.Save(&subscription).Error; err != nil {
+		return subscription, err
+	}
+	if input.Secret == nil {
+		subscription.Secret = ""
+	}
+	return subscription, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с историей доставок.
+func DeleteWebhook(db *gorm.DB, id uint) error {
+	result := db.Delete(&models.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries возвращает историю доставок подписки, новые первыми.
+// status сужает выборку, например dead — журнал недоставленных событий.
+func ListDeliveries(db *gorm.DB, subscriptionID uint, status string, page, pageSize int) ([]models.WebhookDelivery, error) {
+	if _, err := findWebhook(db, subscriptionID); err != nil {
+		return nil, err
+	}
+	query := db.Where("subscription_id = ?", subscriptionID)
+	switch status {
+	case "":
+	case DeliveryStatusPending, DeliveryStatusDelivered, DeliveryStatusDead:
+		query = query.Where("status = ?", status)
+	default:
+		return nil, ErrInvalidStatus
+	}
+	deliveries := []models.WebhookDelivery{}
+	err := query.Order("id DESC").Limit(pageSize).Offset((page - 1) * pageSize).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// RetryDelivery возвращает доставку в очередь с обнулённым счётчиком попыток,
+// например после исправления ошибки на стороне подписчика.
+func RetryDelivery(db *gorm.DB, subscriptionID, deliveryID uint) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := db.Where("id = ? AND subscription_id = ?", deliveryID, subscriptionID).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return delivery, ErrDeliveryNotFound
+		}
+		return delivery, err
+	}
+	delivery.Status = DeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := db.Omit("Subscription").Save(&delivery).Error; err != nil {
+		return delivery, err
+	}
+	return delivery, nil
+}
+
+// StartWebhookDispatcher запускает фоновую отправку вебхуков и очистку
+// старой истории доставок.
+func StartWebhookDispatcher(db *gorm.DB) {
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+		prune := time.NewTicker(webhookPruneInterval)
+		defer prune.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := DispatchWebhooks(db); err != nil {
+					logger.Log.Errorf("Ошибка отправки вебхуков: %v", err)
+				}
+			case <-prune.C:
+				if err := PruneWebhookDeliveries(db); err != nil {
+					logger.Log.Errorf("Ошибка очистки истории доставок: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// PruneWebhookDeliveries удаляет завершённые доставки старше срока хранения.
+// Доставки в очереди не удаляются.
+func PruneWebhookDeliveries(db *gorm.DB) error {
+	now := time.Now()
+	result := db.Where("(status = ? AND updated_at < ?) OR (status = ? AND updated_at < ?)",
+		DeliveryStatusDelivered, now.Add(-webhookDeliveredRetention),
+		DeliveryStatusDead, now.Add(-webhookDeadRetention)).
+		Delete(&models.WebhookDelivery{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Log.Infof("Удалено старых доставок вебхуков: %d", result.RowsAffected)
+	}
+	return nil
+}
+
+// DispatchWebhooks отправляет очередную пачку доставок, срок которых подошёл.
+// Несколько экземпляров сервиса не возьмут одну доставку благодаря SKIP LOCKED и аренде.
+func DispatchWebhooks(db *gorm.DB) error {
+	var deliveries []models.WebhookDelivery
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Subscription").
+			Where("status = ? AND next_attempt_at <= ?", DeliveryStatusPending, time.Now()).
+			Where("subscription_id IN (SELECT id FROM webhook_subscriptions WHERE active)").
+			Order("next_attempt_at, id").
+			Limit(webhookBatchSize).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(webhookLease)).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			if err := deliverWebhook(db, delivery); err != nil {
+				logger.Log.Errorf("Ошибка сохранения результата доставки %d: %v", delivery.ID, err)
+			}
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	return nil
+}
+
+// SignWebhook возвращает подпись тела запроса: hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Подписчик пересчитывает её по заголовкам X-Webhook-Timestamp и X-Webhook-Signature.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverWebhook выполняет одну попытку доставки и сохраняет её результат.
+func deliverWebhook(db *gorm.DB, delivery *models.WebhookDelivery) error {
+	delivery.Attempts++
+	status, sendErr := sendWebhook(delivery)
+	delivery.ResponseStatus = status
+
+	now := time.Now()
+	switch {
+	case sendErr == nil:
+		delivery.Status = DeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = DeliveryStatusDead
+		delivery.LastError = sendErr.Error()
+		logger.Log.Errorf("Вебхук %d (%s) не доставлен после %d попыток: %v",
+			delivery.ID, delivery.EventType, delivery.Attempts, sendErr)
+	default:
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		logger.Log.Debugf("Вебхук %d: попытка %d не удалась, повтор в %s: %v",
+			delivery.ID, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), sendErr)
+	}
+	return db.Model(delivery).Select("status", "attempts", "next_attempt_at", "response_status", "last_error", "delivered_at").
+		Updates(delivery).Error
+}
+
+func sendWebhook(delivery *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, delivery.Subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, SignWebhook(delivery.Subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+		return resp.StatusCode, fmt.Errorf("подписчик ответил %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff — задержка перед следующей попыткой с разбросом ±20%,
+// чтобы повторы к одному подписчику не шли волной.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookMaxBackoff
+	if attempts < 20 {
+		if d := webhookBaseBackoff << (attempts - 1); d < webhookMaxBackoff {
+			delay = d
+		}
+	}
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(float64(delay) * jitter)
+}
+
+// enqueueWebhookDeliveries создаёт доставки события для всех подходящих активных подписок.
+func enqueueWebhookDeliveries(db *gorm.DB, event models.Event) error {
+	var subscriptions []models.WebhookSubscription
+	if err := db.Where("active").Find(&subscriptions).Error; err != nil {
+		return err
+	}
+	var payload []byte
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !eventMatches(subscription.Events, event.Type) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         DeliveryStatusPending,
+			NextAttemptAt:  event.OccurredAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.Omit("Subscription").Create(&deliveries).Error
+}
+
+func findWebhook(db *gorm.DB, id uint) (models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := db.First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return subscription, ErrWebhookNotFound
+		}
+		return subscription, err
+	}
+	return subscription, nil
+}
+
+func validateWebhook(subscription models.WebhookSubscription) error {
+	u, err := url.Parse(subscription.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhook
+	}
+	// Имена проверяются при отправке, после разрешения; здесь отсекаем
+	// очевидное: localhost и внутренние IP-адреса, записанные явно.
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidWebhook
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !publicAddress(addr) {
+		return ErrInvalidWebhook
+	}
+	for _, pattern := range subscription.Events {
+		if !validEventPattern(pattern) {
+			return ErrInvalidWebhook
+		}
+	}
+	return nil
+}
+
+// rejectPrivateAddress запрещает соединения вебхуков с внутренними адресами.
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// carrierNAT — общее адресное пространство операторов (RFC 6598).
+var carrierNAT = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddress сообщает, что адрес не относится к loopback, частным (RFC 1918,
+// fc00::/7), link-local (в том числе 169.254.169.254 метаданных облака),
+// multicast или неуказанному адресу.
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!carrierNAT.Contains(addr)
+}
+
+func normalizeEventPatterns(patterns []string) []string {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern != "" && !seen[pattern] {
+			seen[pattern] = true
+			normalized = append(normalized, pattern)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,226 @@
+// Package client — типизированный клиент REST API каталога песен для Go-сервисов.
+//
+//	c, err := client.New("http://songs:8080", client.WithRetries(3, 200*time.Millisecond))
+//	song, err := c.GetSong(ctx, 42)
+//	if errors.Is(err, client.ErrNotFound) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"songs/internal/models"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 2
+	defaultRetryWait  = 200 * time.Millisecond
+	// Дольше между попытками не ждём, даже если сервер просит в Retry-After.
+	maxRetryWait = 10 * time.Second
+)
+
+// Ошибки по классам HTTP-статусов. Ошибка API сравнивается с ними через errors.Is.
+var (
+	ErrBadRequest   = errors.New("некорректный запрос")
+	ErrUnauthorized = errors.New("требуется авторизация")
+	ErrForbidden    = errors.New("доступ запрещён")
+	ErrNotFound     = errors.New("не найдено")
+	ErrConflict     = errors.New("конфликт")
+	ErrServer       = errors.New("ошибка сервера")
+)
+
+// APIError — ответ API с кодом ошибки и сообщением из models.ErrorResponse.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("songs API: %d: %s", e.StatusCode, e.Message)
+}
+
+// Is позволяет проверять класс ошибки: errors.Is(err, client.ErrNotFound).
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// Client выполняет запросы к API каталога. Безопасен для параллельного использования.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	maxRetries int
+	retryWait  time.Duration
+	header     http.Header
+}
+
+// Option настраивает Client.
+type Option func(*Client)
+
+// WithHTTPClient задаёт HTTP-клиент, например с собственным транспортом или таймаутом.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithRetries задаёт число повторов и начальную паузу между ними. Повторяются
+// только идемпотентные запросы (все, кроме создания песни) при сетевых ошибках,
+// ответах 5xx и 429; пауза удваивается с каждой попыткой.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// WithHeader добавляет заголовок ко всем запросам, например Accept-Language.
+func WithHeader(key, value string) Option {
+	return func(c *Client) { c.header.Add(key, value) }
+}
+
+// New создаёт клиент для API по адресу baseURL, например http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("некорректный адрес API: %q", baseURL)
+	}
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		retryWait:  defaultRetryWait,
+		header:     http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// do выполняет запрос с телом body в JSON и декодирует JSON-ответ в out
+// (если out не nil). Ответ с кодом 4xx/5xx возвращается как *APIError.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	contentType := ""
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+		contentType = "application/json"
+	}
+	resp, err := c.roundTrip(ctx, method, path, query, payload, contentType)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, out)
+}
+
+// roundTrip отправляет запрос, повторяя его при сетевых ошибках и ответах 5xx
+// и 429, и возвращает последний ответ. POST не повторяется.
+func (c *Client) roundTrip(ctx context.Context, method, path string, query url.Values, payload []byte, contentType string) (*http.Response, error) {
+	target := c.baseURL.JoinPath(path)
+	target.RawQuery = query.Encode()
+
+	retries := c.maxRetries
+	if method == http.MethodPost {
+		retries = 0
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, target.String(), payload, contentType)
+		if (err == nil && !retryableStatus(resp.StatusCode)) || attempt >= retries {
+			return resp, err
+		}
+
+		wait := c.retryWait << attempt
+		if err == nil {
+			if after, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil {
+				wait = time.Duration(after) * time.Second
+			}
+			resp.Body.Close()
+		}
+		// Случайная добавка разводит повторы клиентов, упавших одновременно.
+		wait = min(wait+time.Duration(rand.Int63n(int64(wait)/5+1)), maxRetryWait)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, payload []byte, contentType string) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return c.httpClient.Do(req)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		_, err := io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("некорректный ответ API: %w", err)
+	}
+	return nil
+}
+
+// responseError возвращает *APIError для ответа с кодом 4xx/5xx.
+func responseError(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	var body models.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != "" {
+		apiErr.Message = body.Error
+	} else {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
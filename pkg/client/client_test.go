@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c, err := New(server.URL, WithRetries(2, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestGetRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "недоступно"})
+			return
+		}
+		writeJSON(w, http.StatusOK, Song{ID: 42, Song: "Кукушка"})
+	})
+
+	song, err := c.GetSong(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetSong: %v", err)
+	}
+	if song.ID != 42 || song.Song != "Кукушка" {
+		t.Errorf("song = %+v", song)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestGetGivesUpAfterRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "сбой БД"})
+	})
+
+	_, err := c.GetSong(context.Background(), 1)
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("err = %v, want ErrServer", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3 (1 + 2 retries)", got)
+	}
+}
+
+func TestPostIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "нет ответа"})
+	})
+
+	if _, err := c.CreateSong(context.Background(), "Кино", "Кукушка"); !errors.Is(err, ErrServer) {
+		t.Fatalf("err = %v, want ErrServer", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, tt.status, map[string]string{"error": "Песня не найдена"})
+		})
+
+		err := c.DeleteSong(context.Background(), 7)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: err = %v, want %v", tt.status, err, tt.want)
+			continue
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Message != "Песня не найдена" {
+			t.Errorf("status %d: APIError = %+v", tt.status, apiErr)
+		}
+	}
+}
+
+func TestErrorWithoutJSONBody(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not json", http.StatusNotFound)
+	})
+
+	_, err := c.GetSong(context.Background(), 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != http.StatusText(http.StatusNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestPatchSongSendsOnlySetFields(t *testing.T) {
+	var body map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/songs/5" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		writeJSON(w, http.StatusOK, Song{ID: 5})
+	})
+
+	date, err := ParsePartialDate("2006-07")
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := ""
+	if _, err := c.PatchSong(context.Background(), 5, SongUpdate{ReleaseDate: &date, Link: &link}); err != nil {
+		t.Fatalf("PatchSong: %v", err)
+	}
+	if len(body) != 2 || body["releaseDate"] != "2006-07" || body["link"] != "" {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestAllSongsPages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			writeJSON(w, http.StatusOK, []Song{{ID: 1}, {ID: 2}})
+		case "2":
+			writeJSON(w, http.StatusOK, []Song{{ID: 3}})
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	var ids []uint
+	for song, err := range c.AllSongs(context.Background(), ListOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, song.ID)
+	}
+	if len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("ids = %v", ids)
+	}
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const defaultPageSize = 10
+
+// ListOptions — фильтры, сортировка и страница списка песен, те же, что
+// query-параметры GET /songs. Пустые поля не передаются.
+type ListOptions struct {
+	Group        string
+	Role         string
+	Song         string
+	ReleaseDate  string
+	ReleasedFrom string
+	ReleasedTo   string
+	Text         string
+	Link         string
+	Platforms    []string
+	Album        string
+	AlbumID      uint
+	AlbumType    string
+	Genres       []string
+	GenreMode    string
+	Tags         []string
+	TagMode      string
+	// popularity, releaseDate или -releaseDate; по умолчанию по ID.
+	Sort     string
+	Page     int
+	PageSize int
+}
+
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	set("group", o.Group)
+	set("role", o.Role)
+	set("song", o.Song)
+	set("releaseDate", o.ReleaseDate)
+	set("releasedFrom", o.ReleasedFrom)
+	set("releasedTo", o.ReleasedTo)
+	set("text", o.Text)
+	set("link", o.Link)
+	set("platform", strings.Join(o.Platforms, ","))
+	set("album", o.Album)
+	set("albumType", o.AlbumType)
+	set("genre", strings.Join(o.Genres, ","))
+	set("genreMode", o.GenreMode)
+	set("tag", strings.Join(o.Tags, ","))
+	set("tagMode", o.TagMode)
+	set("sort", o.Sort)
+	if o.AlbumID != 0 {
+		values.Set("albumId", strconv.FormatUint(uint64(o.AlbumID), 10))
+	}
+	if o.Page > 0 {
+		values.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		values.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+	return values
+}
+
+// TextOptions — параметры GET /songs/{id}/text.
+type TextOptions struct {
+	// Только секции этого типа: verse, chorus, prechorus, bridge, intro, outro, other.
+	Type string
+	// Язык текста; если перевода нет, возвращается оригинал.
+	Lang string
+	// Язык второй колонки для двуязычного показа.
+	Align    string
+	Page     int
+	PageSize int
+}
+
+func (o TextOptions) values() url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{"type": o.Type, "lang": o.Lang, "align": o.Align} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if o.Page > 0 {
+		values.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		values.Set("pageSize", strconv.Itoa(o.PageSize))
+	}
+	return values
+}
+
+// ListSongs возвращает одну страницу песен.
+func (c *Client) ListSongs(ctx context.Context, opts ListOptions) ([]Song, error) {
+	var songs []Song
+	err := c.do(ctx, http.MethodGet, "/songs", opts.values(), nil, &songs)
+	return songs, err
+}
+
+// AllSongs перебирает все песни под фильтрами, запрашивая страницы по мере
+// чтения, начиная со страницы opts.Page. Перебор останавливается на первой ошибке:
+//
+//	for song, err := range c.AllSongs(ctx, client.ListOptions{Group: "Muse"}) {
+//		if err != nil { return err }
+//		...
+//	}
+func (c *Client) AllSongs(ctx context.Context, opts ListOptions) iter.Seq2[Song, error] {
+	return func(yield func(Song, error) bool) {
+		if opts.Page < 1 {
+			opts.Page = 1
+		}
+		if opts.PageSize < 1 {
+			opts.PageSize = defaultPageSize
+		}
+		for {
+			songs, err := c.ListSongs(ctx, opts)
+			if err != nil {
+				yield(Song{}, err)
+				return
+			}
+			for _, song := range songs {
+				if !yield(song, nil) {
+					return
+				}
+			}
+			if len(songs) < opts.PageSize {
+				return
+			}
+			opts.Page++
+		}
+	}
+}
+
+// GetSong возвращает песню с артистом, участниками, ссылками, жанрами и тегами.
+func (c *Client) GetSong(ctx context.Context, id uint) (Song, error) {
+	var song Song
+	err := c.do(ctx, http.MethodGet, songPath(id), nil, nil, &song)
+	return song, err
+}
+
+// GetSongText возвращает страницу секций текста песни.
+func (c *Client) GetSongText(ctx context.Context, id uint, opts TextOptions) (SongTextResponse, error) {
+	var text SongTextResponse
+	err := c.do(ctx, http.MethodGet, songPath(id)+"/text", opts.values(), nil, &text)
+	return text, err
+}
+
+// AllVerses перебирает все секции текста песни по страницам.
+func (c *Client) AllVerses(ctx context.Context, id uint, opts TextOptions) iter.Seq2[LyricsSection, error] {
+	return func(yield func(LyricsSection, error) bool) {
+		if opts.Page < 1 {
+			opts.Page = 1
+		}
+		if opts.PageSize < 1 {
+			opts.PageSize = 5
+		}
+		for {
+			text, err := c.GetSongText(ctx, id, opts)
+			if err != nil {
+				yield(LyricsSection{}, err)
+				return
+			}
+			for _, section := range text.Sections {
+				if !yield(section, nil) {
+					return
+				}
+			}
+			if len(text.Sections) < opts.PageSize {
+				return
+			}
+			opts.Page++
+		}
+	}
+}
+
+// CreateSong добавляет песню; сервис дополняет её данными внешнего API.
+// Запрос не повторяется автоматически: повтор мог бы создать песню дважды.
+func (c *Client) CreateSong(ctx context.Context, group, song string) (Song, error) {
+	var created Song
+	err := c.do(ctx, http.MethodPost, "/songs", nil, map[string]string{"group": group, "song": song}, &created)
+	return created, err
+}
+
+// CreateSongWithoutEnrichment добавляет песню с переданными датой релиза,
+// текстом и ссылкой, не обращаясь к внешнему API.
+func (c *Client) CreateSongWithoutEnrichment(ctx context.Context, group, song string, detail SongDetail) (Song, error) {
+	body := map[string]string{
+		"group":       group,
+		"song":        song,
+		"releaseDate": detail.ReleaseDate,
+		"text":        detail.Text,
+		"link":        detail.Link,
+	}
+	var created Song
+	err := c.do(ctx, http.MethodPost, "/songs", url.Values{"enrich": {"false"}}, body, &created)
+	return created, err
+}
+
+// PatchSong изменяет переданные (не nil) поля песни и возвращает её новую версию.
+func (c *Client) PatchSong(ctx context.Context, id uint, update SongUpdate) (Song, error) {
+	var song Song
+	err := c.do(ctx, http.MethodPatch, songPath(id), nil, update, &song)
+	return song, err
+}
+
+// DeleteSong удаляет песню.
+func (c *Client) DeleteSong(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, songPath(id), nil, nil, nil)
+}
+
+// RestoreSong восстанавливает удалённую песню по её последнему событию удаления.
+func (c *Client) RestoreSong(ctx context.Context, id uint) (Song, error) {
+	var song Song
+	err := c.do(ctx, http.MethodPost, songPath(id)+"/restore", nil, nil, &song)
+	return song, err
+}
+
+// EnrichSong заново запрашивает данные песни во внешнем API.
+func (c *Client) EnrichSong(ctx context.Context, id uint) (Song, error) {
+	var song Song
+	err := c.do(ctx, http.MethodPost, songPath(id)+"/enrich", nil, nil, &song)
+	return song, err
+}
+
+// SearchSongs ищет песни по названию, артисту и тексту с учётом опечаток.
+// limit <= 0 — значение сервера по умолчанию.
+func (c *Client) SearchSongs(ctx context.Context, query string, limit int) (SongSearchResponse, error) {
+	values := url.Values{"q": {query}}
+	if limit > 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	var response SongSearchResponse
+	err := c.do(ctx, http.MethodGet, "/songs/search", values, nil, &response)
+	return response, err
+}
+
+func songPath(id uint) string {
+	return "/songs/" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ImportOptions — параметры POST /songs/import.
+type ImportOptions struct {
+	// csv или jsonl.
+	Format string
+	// Только проверить строки, ничего не сохраняя.
+	DryRun bool
+	// Дополнять недостающие поля через внешний API.
+	Enrich bool
+}
+
+// ExportSongs выгружает песни под фильтрами в формате json, csv или ndjson и
+// пишет ответ в w по мере получения. Страница из opts не учитывается.
+func (c *Client) ExportSongs(ctx context.Context, w io.Writer, format string, opts ListOptions) error {
+	query := opts.values()
+	query.Del("page")
+	query.Del("pageSize")
+	query.Del("sort")
+	if format != "" {
+		query.Set("format", format)
+	}
+	resp, err := c.roundTrip(ctx, http.MethodGet, "/songs/export", query, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := responseError(resp); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// ImportSongs отправляет файл импорта и возвращает созданную задачу. Импорт
+// выполняется в фоне; дождаться его можно через WaitImport.
+func (c *Client) ImportSongs(ctx context.Context, r io.Reader, opts ImportOptions) (ImportJob, error) {
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return ImportJob{}, err
+	}
+	query := url.Values{}
+	if opts.Format != "" {
+		query.Set("format", opts.Format)
+	}
+	if opts.DryRun {
+		query.Set("dryRun", "true")
+	}
+	if opts.Enrich {
+		query.Set("enrich", "true")
+	}
+	resp, err := c.roundTrip(ctx, http.MethodPost, "/songs/import", query, payload, "application/octet-stream")
+	if err != nil {
+		return ImportJob{}, err
+	}
+	var job ImportJob
+	err = decodeResponse(resp, &job)
+	return job, err
+}
+
+// GetImportJob возвращает состояние задачи импорта.
+func (c *Client) GetImportJob(ctx context.Context, id string) (ImportJob, error) {
+	var job ImportJob
+	err := c.do(ctx, http.MethodGet, "/songs/import/"+url.PathEscape(id), nil, nil, &job)
+	return job, err
+}
+
+// WaitImport опрашивает задачу импорта с интервалом interval, пока она не завершится.
+func (c *Client) WaitImport(ctx context.Context, id string, interval time.Duration) (ImportJob, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := c.GetImportJob(ctx, id)
+		if err != nil || job.FinishedAt != nil {
+			return job, err
+		}
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package client
+
+import "songs/internal/models"
+
+// Типы ответов и запросов API. Это псевдонимы моделей сервиса: пакет
+// internal/models недоступен вне модуля, поэтому внешние сервисы работают с
+// ними через эти имена.
+type (
+	Song               = models.Song
+	Artist             = models.Artist
+	ArtistAlias        = models.ArtistAlias
+	SongLink           = models.SongLink
+	SongCredit         = models.SongCredit
+	Genre              = models.Genre
+	Tag                = models.Tag
+	SongUpdate         = models.SongUpdate
+	SongDetail         = models.SongDetail
+	PartialDate        = models.PartialDate
+	SongTextResponse   = models.SongTextResponse
+	LyricsSection      = models.LyricsSection
+	AlignedSection     = models.AlignedSection
+	SongSearchResponse = models.SongSearchResponse
+	SongSearchResult   = models.SongSearchResult
+	ImportJob          = models.ImportJob
+	ImportRowResult    = models.ImportRowResult
+)
+
+// ParsePartialDate разбирает дату релиза для SongUpdate.ReleaseDate:
+// «2006», «2006-07», «2006-07-16» и другие форматы, которые принимает API.
+func ParsePartialDate(value string) (PartialDate, error) {
+	return models.ParsePartialDate(value)
+}
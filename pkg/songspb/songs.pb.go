@@ -0,0 +1,1343 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: songs/v1/songs.proto
+
+package songspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Artist struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Aliases       []string               `protobuf:"bytes,3,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Artist) Reset() {
+	*x = Artist{}
+	mi := &file_songs_v1_songs_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artist) ProtoMessage() {}
+
+func (x *Artist) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artist.ProtoReflect.Descriptor instead.
+func (*Artist) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Artist) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Artist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Artist) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *Artist) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Artist) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Link struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Platform      string                 `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_songs_v1_songs_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Link) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Link) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *Link) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Link) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Song struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ArtistId uint64                 `protobuf:"varint,2,opt,name=artist_id,json=artistId,proto3" json:"artist_id,omitempty"`
+	Artist   *Artist                `protobuf:"bytes,3,opt,name=artist,proto3" json:"artist,omitempty"`
+	Song     string                 `protobuf:"bytes,4,opt,name=song,proto3" json:"song,omitempty"`
+	// YYYY, YYYY-MM или YYYY-MM-DD; пусто, если дата неизвестна.
+	ReleaseDate   string                 `protobuf:"bytes,5,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	Text          string                 `protobuf:"bytes,6,opt,name=text,proto3" json:"text,omitempty"`
+	Link          string                 `protobuf:"bytes,7,opt,name=link,proto3" json:"link,omitempty"`
+	Lang          string                 `protobuf:"bytes,8,opt,name=lang,proto3" json:"lang,omitempty"`
+	Links         []*Link                `protobuf:"bytes,9,rep,name=links,proto3" json:"links,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Song) Reset() {
+	*x = Song{}
+	mi := &file_songs_v1_songs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Song) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Song) ProtoMessage() {}
+
+func (x *Song) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Song.ProtoReflect.Descriptor instead.
+func (*Song) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Song) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Song) GetArtistId() uint64 {
+	if x != nil {
+		return x.ArtistId
+	}
+	return 0
+}
+
+func (x *Song) GetArtist() *Artist {
+	if x != nil {
+		return x.Artist
+	}
+	return nil
+}
+
+func (x *Song) GetSong() string {
+	if x != nil {
+		return x.Song
+	}
+	return ""
+}
+
+func (x *Song) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *Song) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Song) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *Song) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *Song) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *Song) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Song) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Фильтры списка песен, те же, что query-параметры GET /songs.
+type SongFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Song          string                 `protobuf:"bytes,3,opt,name=song,proto3" json:"song,omitempty"`
+	ReleaseDate   string                 `protobuf:"bytes,4,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	ReleasedFrom  string                 `protobuf:"bytes,5,opt,name=released_from,json=releasedFrom,proto3" json:"released_from,omitempty"`
+	ReleasedTo    string                 `protobuf:"bytes,6,opt,name=released_to,json=releasedTo,proto3" json:"released_to,omitempty"`
+	Text          string                 `protobuf:"bytes,7,opt,name=text,proto3" json:"text,omitempty"`
+	Link          string                 `protobuf:"bytes,8,opt,name=link,proto3" json:"link,omitempty"`
+	Platform      []string               `protobuf:"bytes,9,rep,name=platform,proto3" json:"platform,omitempty"`
+	Album         string                 `protobuf:"bytes,10,opt,name=album,proto3" json:"album,omitempty"`
+	AlbumId       *uint64                `protobuf:"varint,11,opt,name=album_id,json=albumId,proto3,oneof" json:"album_id,omitempty"`
+	AlbumType     string                 `protobuf:"bytes,12,opt,name=album_type,json=albumType,proto3" json:"album_type,omitempty"`
+	Genre         []string               `protobuf:"bytes,13,rep,name=genre,proto3" json:"genre,omitempty"`
+	GenreMode     string                 `protobuf:"bytes,14,opt,name=genre_mode,json=genreMode,proto3" json:"genre_mode,omitempty"`
+	Tag           []string               `protobuf:"bytes,15,rep,name=tag,proto3" json:"tag,omitempty"`
+	TagMode       string                 `protobuf:"bytes,16,opt,name=tag_mode,json=tagMode,proto3" json:"tag_mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SongFilter) Reset() {
+	*x = SongFilter{}
+	mi := &file_songs_v1_songs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SongFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SongFilter) ProtoMessage() {}
+
+func (x *SongFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SongFilter.ProtoReflect.Descriptor instead.
+func (*SongFilter) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SongFilter) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *SongFilter) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *SongFilter) GetSong() string {
+	if x != nil {
+		return x.Song
+	}
+	return ""
+}
+
+func (x *SongFilter) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *SongFilter) GetReleasedFrom() string {
+	if x != nil {
+		return x.ReleasedFrom
+	}
+	return ""
+}
+
+func (x *SongFilter) GetReleasedTo() string {
+	if x != nil {
+		return x.ReleasedTo
+	}
+	return ""
+}
+
+func (x *SongFilter) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SongFilter) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *SongFilter) GetPlatform() []string {
+	if x != nil {
+		return x.Platform
+	}
+	return nil
+}
+
+func (x *SongFilter) GetAlbum() string {
+	if x != nil {
+		return x.Album
+	}
+	return ""
+}
+
+func (x *SongFilter) GetAlbumId() uint64 {
+	if x != nil && x.AlbumId != nil {
+		return *x.AlbumId
+	}
+	return 0
+}
+
+func (x *SongFilter) GetAlbumType() string {
+	if x != nil {
+		return x.AlbumType
+	}
+	return ""
+}
+
+func (x *SongFilter) GetGenre() []string {
+	if x != nil {
+		return x.Genre
+	}
+	return nil
+}
+
+func (x *SongFilter) GetGenreMode() string {
+	if x != nil {
+		return x.GenreMode
+	}
+	return ""
+}
+
+func (x *SongFilter) GetTag() []string {
+	if x != nil {
+		return x.Tag
+	}
+	return nil
+}
+
+func (x *SongFilter) GetTagMode() string {
+	if x != nil {
+		return x.TagMode
+	}
+	return ""
+}
+
+type ListSongsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *SongFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// popularity, releaseDate или -releaseDate; по умолчанию по ID.
+	Sort          string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	Page          int32  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSongsRequest) Reset() {
+	*x = ListSongsRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSongsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSongsRequest) ProtoMessage() {}
+
+func (x *ListSongsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSongsRequest.ProtoReflect.Descriptor instead.
+func (*ListSongsRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSongsRequest) GetFilter() *SongFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListSongsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListSongsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSongsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListSongsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Songs         []*Song                `protobuf:"bytes,1,rep,name=songs,proto3" json:"songs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSongsResponse) Reset() {
+	*x = ListSongsResponse{}
+	mi := &file_songs_v1_songs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSongsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSongsResponse) ProtoMessage() {}
+
+func (x *ListSongsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSongsResponse.ProtoReflect.Descriptor instead.
+func (*ListSongsResponse) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSongsResponse) GetSongs() []*Song {
+	if x != nil {
+		return x.Songs
+	}
+	return nil
+}
+
+type StreamSongsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *SongFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSongsRequest) Reset() {
+	*x = StreamSongsRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSongsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSongsRequest) ProtoMessage() {}
+
+func (x *StreamSongsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSongsRequest.ProtoReflect.Descriptor instead.
+func (*StreamSongsRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StreamSongsRequest) GetFilter() *SongFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type GetSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSongRequest) Reset() {
+	*x = GetSongRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSongRequest) ProtoMessage() {}
+
+func (x *GetSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSongRequest.ProtoReflect.Descriptor instead.
+func (*GetSongRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSongRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         string                 `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Song          string                 `protobuf:"bytes,2,opt,name=song,proto3" json:"song,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSongRequest) Reset() {
+	*x = CreateSongRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSongRequest) ProtoMessage() {}
+
+func (x *CreateSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSongRequest.ProtoReflect.Descriptor instead.
+func (*CreateSongRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateSongRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *CreateSongRequest) GetSong() string {
+	if x != nil {
+		return x.Song
+	}
+	return ""
+}
+
+type UpdateSongRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Новое имя основного исполнителя: меняется во всех его песнях.
+	Group *string `protobuf:"bytes,2,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Song  *string `protobuf:"bytes,3,opt,name=song,proto3,oneof" json:"song,omitempty"`
+	// Новая дата релиза; пустая строка сбрасывает дату.
+	ReleaseDate *string `protobuf:"bytes,4,opt,name=release_date,json=releaseDate,proto3,oneof" json:"release_date,omitempty"`
+	Text        *string `protobuf:"bytes,5,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Link        *string `protobuf:"bytes,6,opt,name=link,proto3,oneof" json:"link,omitempty"`
+	Lang        *string `protobuf:"bytes,7,opt,name=lang,proto3,oneof" json:"lang,omitempty"`
+	// Перенос песни к другому артисту; "A feat. B" пересобирает участников.
+	MoveTo        *string `protobuf:"bytes,8,opt,name=move_to,json=moveTo,proto3,oneof" json:"move_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSongRequest) Reset() {
+	*x = UpdateSongRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSongRequest) ProtoMessage() {}
+
+func (x *UpdateSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSongRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSongRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateSongRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateSongRequest) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetSong() string {
+	if x != nil && x.Song != nil {
+		return *x.Song
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetReleaseDate() string {
+	if x != nil && x.ReleaseDate != nil {
+		return *x.ReleaseDate
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetLink() string {
+	if x != nil && x.Link != nil {
+		return *x.Link
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetLang() string {
+	if x != nil && x.Lang != nil {
+		return *x.Lang
+	}
+	return ""
+}
+
+func (x *UpdateSongRequest) GetMoveTo() string {
+	if x != nil && x.MoveTo != nil {
+		return *x.MoveTo
+	}
+	return ""
+}
+
+type DeleteSongRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSongRequest) Reset() {
+	*x = DeleteSongRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSongRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSongRequest) ProtoMessage() {}
+
+func (x *DeleteSongRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSongRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSongRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteSongRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteSongResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSongResponse) Reset() {
+	*x = DeleteSongResponse{}
+	mi := &file_songs_v1_songs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSongResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSongResponse) ProtoMessage() {}
+
+func (x *DeleteSongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSongResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSongResponse) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{11}
+}
+
+// Куплеты — секции текста, как поле verses в GET /songs/{id}/text: текст
+// берётся из перевода на языке lang, синхронизированного текста или текста песни
+// и делится по пустым строкам и меткам вида [Chorus], которые в куплеты не попадают.
+type GetVersesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Page  int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	// По умолчанию 5, не больше 100.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Язык текста; если перевода нет, возвращается оригинал.
+	Lang          string `protobuf:"bytes,4,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersesRequest) Reset() {
+	*x = GetVersesRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersesRequest) ProtoMessage() {}
+
+func (x *GetVersesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersesRequest.ProtoReflect.Descriptor instead.
+func (*GetVersesRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetVersesRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetVersesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetVersesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetVersesRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type GetVersesResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Verses   []string               `protobuf:"bytes,1,rep,name=verses,proto3" json:"verses,omitempty"`
+	Page     int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Total    int32                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	// Язык возвращённого текста.
+	Lang          string `protobuf:"bytes,5,opt,name=lang,proto3" json:"lang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersesResponse) Reset() {
+	*x = GetVersesResponse{}
+	mi := &file_songs_v1_songs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersesResponse) ProtoMessage() {}
+
+func (x *GetVersesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersesResponse.ProtoReflect.Descriptor instead.
+func (*GetVersesResponse) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetVersesResponse) GetVerses() []string {
+	if x != nil {
+		return x.Verses
+	}
+	return nil
+}
+
+func (x *GetVersesResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetVersesResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetVersesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetVersesResponse) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type GetArtistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArtistRequest) Reset() {
+	*x = GetArtistRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArtistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArtistRequest) ProtoMessage() {}
+
+func (x *GetArtistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArtistRequest.ProtoReflect.Descriptor instead.
+func (*GetArtistRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetArtistRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type LookupArtistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupArtistRequest) Reset() {
+	*x = LookupArtistRequest{}
+	mi := &file_songs_v1_songs_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupArtistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupArtistRequest) ProtoMessage() {}
+
+func (x *LookupArtistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_songs_v1_songs_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupArtistRequest.ProtoReflect.Descriptor instead.
+func (*LookupArtistRequest) Descriptor() ([]byte, []int) {
+	return file_songs_v1_songs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LookupArtistRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+var File_songs_v1_songs_proto protoreflect.FileDescriptor
+
+const file_songs_v1_songs_proto_rawDesc = "" +
+	"\n" +
+	"\x14songs/v1/songs.proto\x12\bsongs.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbc\x01\n" +
+	"\x06Artist\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aaliases\x18\x03 \x03(\tR\aaliases\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"X\n" +
+	"\x04Link\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\bplatform\x18\x02 \x01(\tR\bplatform\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"\xec\x02\n" +
+	"\x04Song\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tartist_id\x18\x02 \x01(\x04R\bartistId\x12(\n" +
+	"\x06artist\x18\x03 \x01(\v2\x10.songs.v1.ArtistR\x06artist\x12\x12\n" +
+	"\x04song\x18\x04 \x01(\tR\x04song\x12!\n" +
+	"\frelease_date\x18\x05 \x01(\tR\vreleaseDate\x12\x12\n" +
+	"\x04text\x18\x06 \x01(\tR\x04text\x12\x12\n" +
+	"\x04link\x18\a \x01(\tR\x04link\x12\x12\n" +
+	"\x04lang\x18\b \x01(\tR\x04lang\x12$\n" +
+	"\x05links\x18\t \x03(\v2\x0e.songs.v1.LinkR\x05links\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xbb\x03\n" +
+	"\n" +
+	"SongFilter\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x12\n" +
+	"\x04song\x18\x03 \x01(\tR\x04song\x12!\n" +
+	"\frelease_date\x18\x04 \x01(\tR\vreleaseDate\x12#\n" +
+	"\rreleased_from\x18\x05 \x01(\tR\freleasedFrom\x12\x1f\n" +
+	"\vreleased_to\x18\x06 \x01(\tR\n" +
+	"releasedTo\x12\x12\n" +
+	"\x04text\x18\a \x01(\tR\x04text\x12\x12\n" +
+	"\x04link\x18\b \x01(\tR\x04link\x12\x1a\n" +
+	"\bplatform\x18\t \x03(\tR\bplatform\x12\x14\n" +
+	"\x05album\x18\n" +
+	" \x01(\tR\x05album\x12\x1e\n" +
+	"\balbum_id\x18\v \x01(\x04H\x00R\aalbumId\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"album_type\x18\f \x01(\tR\talbumType\x12\x14\n" +
+	"\x05genre\x18\r \x03(\tR\x05genre\x12\x1d\n" +
+	"\n" +
+	"genre_mode\x18\x0e \x01(\tR\tgenreMode\x12\x10\n" +
+	"\x03tag\x18\x0f \x03(\tR\x03tag\x12\x19\n" +
+	"\btag_mode\x18\x10 \x01(\tR\atagModeB\v\n" +
+	"\t_album_id\"\x85\x01\n" +
+	"\x10ListSongsRequest\x12,\n" +
+	"\x06filter\x18\x01 \x01(\v2\x14.songs.v1.SongFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"9\n" +
+	"\x11ListSongsResponse\x12$\n" +
+	"\x05songs\x18\x01 \x03(\v2\x0e.songs.v1.SongR\x05songs\"B\n" +
+	"\x12StreamSongsRequest\x12,\n" +
+	"\x06filter\x18\x01 \x01(\v2\x14.songs.v1.SongFilterR\x06filter\" \n" +
+	"\x0eGetSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"=\n" +
+	"\x11CreateSongRequest\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\x12\x12\n" +
+	"\x04song\x18\x02 \x01(\tR\x04song\"\xb3\x02\n" +
+	"\x11UpdateSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x19\n" +
+	"\x05group\x18\x02 \x01(\tH\x00R\x05group\x88\x01\x01\x12\x17\n" +
+	"\x04song\x18\x03 \x01(\tH\x01R\x04song\x88\x01\x01\x12&\n" +
+	"\frelease_date\x18\x04 \x01(\tH\x02R\vreleaseDate\x88\x01\x01\x12\x17\n" +
+	"\x04text\x18\x05 \x01(\tH\x03R\x04text\x88\x01\x01\x12\x17\n" +
+	"\x04link\x18\x06 \x01(\tH\x04R\x04link\x88\x01\x01\x12\x17\n" +
+	"\x04lang\x18\a \x01(\tH\x05R\x04lang\x88\x01\x01\x12\x1c\n" +
+	"\amove_to\x18\b \x01(\tH\x06R\x06moveTo\x88\x01\x01B\b\n" +
+	"\x06_groupB\a\n" +
+	"\x05_songB\x0f\n" +
+	"\r_release_dateB\a\n" +
+	"\x05_textB\a\n" +
+	"\x05_linkB\a\n" +
+	"\x05_langB\n" +
+	"\n" +
+	"\b_move_to\"#\n" +
+	"\x11DeleteSongRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x14\n" +
+	"\x12DeleteSongResponse\"g\n" +
+	"\x10GetVersesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x12\n" +
+	"\x04lang\x18\x04 \x01(\tR\x04lang\"\x86\x01\n" +
+	"\x11GetVersesResponse\x12\x16\n" +
+	"\x06verses\x18\x01 \x03(\tR\x06verses\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04lang\x18\x05 \x01(\tR\x04lang\"\"\n" +
+	"\x10GetArtistRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\")\n" +
+	"\x13LookupArtistRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name2\xc9\x04\n" +
+	"\fSongsService\x12D\n" +
+	"\tListSongs\x12\x1a.songs.v1.ListSongsRequest\x1a\x1b.songs.v1.ListSongsResponse\x12=\n" +
+	"\vStreamSongs\x12\x1c.songs.v1.StreamSongsRequest\x1a\x0e.songs.v1.Song0\x01\x123\n" +
+	"\aGetSong\x12\x18.songs.v1.GetSongRequest\x1a\x0e.songs.v1.Song\x129\n" +
+	"\n" +
+	"CreateSong\x12\x1b.songs.v1.CreateSongRequest\x1a\x0e.songs.v1.Song\x129\n" +
+	"\n" +
+	"UpdateSong\x12\x1b.songs.v1.UpdateSongRequest\x1a\x0e.songs.v1.Song\x12G\n" +
+	"\n" +
+	"DeleteSong\x12\x1b.songs.v1.DeleteSongRequest\x1a\x1c.songs.v1.DeleteSongResponse\x12D\n" +
+	"\tGetVerses\x12\x1a.songs.v1.GetVersesRequest\x1a\x1b.songs.v1.GetVersesResponse\x129\n" +
+	"\tGetArtist\x12\x1a.songs.v1.GetArtistRequest\x1a\x10.songs.v1.Artist\x12?\n" +
+	"\fLookupArtist\x12\x1d.songs.v1.LookupArtistRequest\x1a\x10.songs.v1.ArtistB\x1bZ\x19songs/pkg/songspb;songspbb\x06proto3"
+
+var (
+	file_songs_v1_songs_proto_rawDescOnce sync.Once
+	file_songs_v1_songs_proto_rawDescData []byte
+)
+
+func file_songs_v1_songs_proto_rawDescGZIP() []byte {
+	file_songs_v1_songs_proto_rawDescOnce.Do(func() {
+		file_songs_v1_songs_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_songs_v1_songs_proto_rawDesc), len(file_songs_v1_songs_proto_rawDesc)))
+	})
+	return file_songs_v1_songs_proto_rawDescData
+}
+
+var file_songs_v1_songs_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_songs_v1_songs_proto_goTypes = []any{
+	(*Artist)(nil),                // 0: songs.v1.Artist
+	(*Link)(nil),                  // 1: songs.v1.Link
+	(*Song)(nil),                  // 2: songs.v1.Song
+	(*SongFilter)(nil),            // 3: songs.v1.SongFilter
+	(*ListSongsRequest)(nil),      // 4: songs.v1.ListSongsRequest
+	(*ListSongsResponse)(nil),     // 5: songs.v1.ListSongsResponse
+	(*StreamSongsRequest)(nil),    // 6: songs.v1.StreamSongsRequest
+	(*GetSongRequest)(nil),        // 7: songs.v1.GetSongRequest
+	(*CreateSongRequest)(nil),     // 8: songs.v1.CreateSongRequest
+	(*UpdateSongRequest)(nil),     // 9: songs.v1.UpdateSongRequest
+	(*DeleteSongRequest)(nil),     // 10: songs.v1.DeleteSongRequest
+	(*DeleteSongResponse)(nil),    // 11: songs.v1.DeleteSongResponse
+	(*GetVersesRequest)(nil),      // 12: songs.v1.GetVersesRequest
+	(*GetVersesResponse)(nil),     // 13: songs.v1.GetVersesResponse
+	(*GetArtistRequest)(nil),      // 14: songs.v1.GetArtistRequest
+	(*LookupArtistRequest)(nil),   // 15: songs.v1.LookupArtistRequest
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_songs_v1_songs_proto_depIdxs = []int32{
+	16, // 0: songs.v1.Artist.created_at:type_name -> google.protobuf.Timestamp
+	16, // 1: songs.v1.Artist.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: songs.v1.Song.artist:type_name -> songs.v1.Artist
+	1,  // 3: songs.v1.Song.links:type_name -> songs.v1.Link
+	16, // 4: songs.v1.Song.created_at:type_name -> google.protobuf.Timestamp
+	16, // 5: songs.v1.Song.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 6: songs.v1.ListSongsRequest.filter:type_name -> songs.v1.SongFilter
+	2,  // 7: songs.v1.ListSongsResponse.songs:type_name -> songs.v1.Song
+	3,  // 8: songs.v1.StreamSongsRequest.filter:type_name -> songs.v1.SongFilter
+	4,  // 9: songs.v1.SongsService.ListSongs:input_type -> songs.v1.ListSongsRequest
+	6,  // 10: songs.v1.SongsService.StreamSongs:input_type -> songs.v1.StreamSongsRequest
+	7,  // 11: songs.v1.SongsService.GetSong:input_type -> songs.v1.GetSongRequest
+	8,  // 12: songs.v1.SongsService.CreateSong:input_type -> songs.v1.CreateSongRequest
+	9,  // 13: songs.v1.SongsService.UpdateSong:input_type -> songs.v1.UpdateSongRequest
+	10, // 14: songs.v1.SongsService.DeleteSong:input_type -> songs.v1.DeleteSongRequest
+	12, // 15: songs.v1.SongsService.GetVerses:input_type -> songs.v1.GetVersesRequest
+	14, // 16: songs.v1.SongsService.GetArtist:input_type -> songs.v1.GetArtistRequest
+	15, // 17: songs.v1.SongsService.LookupArtist:input_type -> songs.v1.LookupArtistRequest
+	5,  // 18: songs.v1.SongsService.ListSongs:output_type -> songs.v1.ListSongsResponse
+	2,  // 19: songs.v1.SongsService.StreamSongs:output_type -> songs.v1.Song
+	2,  // 20: songs.v1.SongsService.GetSong:output_type -> songs.v1.Song
+	2,  // 21: songs.v1.SongsService.CreateSong:output_type -> songs.v1.Song
+	2,  // 22: songs.v1.SongsService.UpdateSong:output_type -> songs.v1.Song
+	11, // 23: songs.v1.SongsService.DeleteSong:output_type -> songs.v1.DeleteSongResponse
+	13, // 24: songs.v1.SongsService.GetVerses:output_type -> songs.v1.GetVersesResponse
+	0,  // 25: songs.v1.SongsService.GetArtist:output_type -> songs.v1.Artist
+	0,  // 26: songs.v1.SongsService.LookupArtist:output_type -> songs.v1.Artist
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_songs_v1_songs_proto_init() }
+func file_songs_v1_songs_proto_init() {
+	if File_songs_v1_songs_proto != nil {
+		return
+	}
+	file_songs_v1_songs_proto_msgTypes[3].OneofWrappers = []any{}
+	file_songs_v1_songs_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_songs_v1_songs_proto_rawDesc), len(file_songs_v1_songs_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_songs_v1_songs_proto_goTypes,
+		DependencyIndexes: file_songs_v1_songs_proto_depIdxs,
+		MessageInfos:      file_songs_v1_songs_proto_msgTypes,
+	}.Build()
+	File_songs_v1_songs_proto = out.File
+	file_songs_v1_songs_proto_goTypes = nil
+	file_songs_v1_songs_proto_depIdxs = nil
+}
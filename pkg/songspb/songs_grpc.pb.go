@@ -0,0 +1,449 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: songs/v1/songs.proto
+
+package songspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SongsService_ListSongs_FullMethodName    = "/songs.v1.SongsService/ListSongs"
+	SongsService_StreamSongs_FullMethodName  = "/songs.v1.SongsService/StreamSongs"
+	SongsService_GetSong_FullMethodName      = "/songs.v1.SongsService/GetSong"
+	SongsService_CreateSong_FullMethodName   = "/songs.v1.SongsService/CreateSong"
+	SongsService_UpdateSong_FullMethodName   = "/songs.v1.SongsService/UpdateSong"
+	SongsService_DeleteSong_FullMethodName   = "/songs.v1.SongsService/DeleteSong"
+	SongsService_GetVerses_FullMethodName    = "/songs.v1.SongsService/GetVerses"
+	SongsService_GetArtist_FullMethodName    = "/songs.v1.SongsService/GetArtist"
+	SongsService_LookupArtist_FullMethodName = "/songs.v1.SongsService/LookupArtist"
+)
+
+// SongsServiceClient is the client API for SongsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Каталог песен по gRPC: те же операции и та же бизнес-логика, что у REST API.
+// Код для Go генерируется в pkg/songspb командой buf generate из корня репозитория.
+type SongsServiceClient interface {
+	// Страница песен с фильтрами и сортировкой, как GET /songs.
+	ListSongs(ctx context.Context, in *ListSongsRequest, opts ...grpc.CallOption) (*ListSongsResponse, error)
+	// Все песни под фильтрами по порядку ID, как GET /songs/export, потоком:
+	// песни читаются из БД пачками и отправляются по мере чтения.
+	StreamSongs(ctx context.Context, in *StreamSongsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Song], error)
+	GetSong(ctx context.Context, in *GetSongRequest, opts ...grpc.CallOption) (*Song, error)
+	// Добавляет песню с обогащением через внешний API, как POST /songs.
+	CreateSong(ctx context.Context, in *CreateSongRequest, opts ...grpc.CallOption) (*Song, error)
+	// Изменяет только переданные поля, как PATCH /songs/{id}.
+	UpdateSong(ctx context.Context, in *UpdateSongRequest, opts ...grpc.CallOption) (*Song, error)
+	DeleteSong(ctx context.Context, in *DeleteSongRequest, opts ...grpc.CallOption) (*DeleteSongResponse, error)
+	// Страница куплетов текста песни.
+	GetVerses(ctx context.Context, in *GetVersesRequest, opts ...grpc.CallOption) (*GetVersesResponse, error)
+	GetArtist(ctx context.Context, in *GetArtistRequest, opts ...grpc.CallOption) (*Artist, error)
+	// Поиск артиста по точному имени или псевдониму без учёта регистра.
+	LookupArtist(ctx context.Context, in *LookupArtistRequest, opts ...grpc.CallOption) (*Artist, error)
+}
+
+type songsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSongsServiceClient(cc grpc.ClientConnInterface) SongsServiceClient {
+	return &songsServiceClient{cc}
+}
+
+func (c *songsServiceClient) ListSongs(ctx context.Context, in *ListSongsRequest, opts ...grpc.CallOption) (*ListSongsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSongsResponse)
+	err := c.cc.Invoke(ctx, SongsService_ListSongs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) StreamSongs(ctx context.Context, in *StreamSongsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Song], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SongsService_ServiceDesc.Streams[0], SongsService_StreamSongs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSongsRequest, Song]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SongsService_StreamSongsClient = grpc.ServerStreamingClient[Song]
+
+func (c *songsServiceClient) GetSong(ctx context.Context, in *GetSongRequest, opts ...grpc.CallOption) (*Song, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Song)
+	err := c.cc.Invoke(ctx, SongsService_GetSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) CreateSong(ctx context.Context, in *CreateSongRequest, opts ...grpc.CallOption) (*Song, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Song)
+	err := c.cc.Invoke(ctx, SongsService_CreateSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) UpdateSong(ctx context.Context, in *UpdateSongRequest, opts ...grpc.CallOption) (*Song, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Song)
+	err := c.cc.Invoke(ctx, SongsService_UpdateSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) DeleteSong(ctx context.Context, in *DeleteSongRequest, opts ...grpc.CallOption) (*DeleteSongResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSongResponse)
+	err := c.cc.Invoke(ctx, SongsService_DeleteSong_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) GetVerses(ctx context.Context, in *GetVersesRequest, opts ...grpc.CallOption) (*GetVersesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVersesResponse)
+	err := c.cc.Invoke(ctx, SongsService_GetVerses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) GetArtist(ctx context.Context, in *GetArtistRequest, opts ...grpc.CallOption) (*Artist, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Artist)
+	err := c.cc.Invoke(ctx, SongsService_GetArtist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *songsServiceClient) LookupArtist(ctx context.Context, in *LookupArtistRequest, opts ...grpc.CallOption) (*Artist, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Artist)
+	err := c.cc.Invoke(ctx, SongsService_LookupArtist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SongsServiceServer is the server API for SongsService service.
+// All implementations must embed UnimplementedSongsServiceServer
+// for forward compatibility.
+//
+// Каталог песен по gRPC: те же операции и та же бизнес-логика, что у REST API.
+// Код для Go генерируется в pkg/songspb командой buf generate из корня репозитория.
+type SongsServiceServer interface {
+	// Страница песен с фильтрами и сортировкой, как GET /songs.
+	ListSongs(context.Context, *ListSongsRequest) (*ListSongsResponse, error)
+	// Все песни под фильтрами по порядку ID, как GET /songs/export, потоком:
+	// песни читаются из БД пачками и отправляются по мере чтения.
+	StreamSongs(*StreamSongsRequest, grpc.ServerStreamingServer[Song]) error
+	GetSong(context.Context, *GetSongRequest) (*Song, error)
+	// Добавляет песню с обогащением через внешний API, как POST /songs.
+	CreateSong(context.Context, *CreateSongRequest) (*Song, error)
+	// Изменяет только переданные поля, как PATCH /songs/{id}.
+	UpdateSong(context.Context, *UpdateSongRequest) (*Song, error)
+	DeleteSong(context.Context, *DeleteSongRequest) (*DeleteSongResponse, error)
+	// Страница куплетов текста песни.
+	GetVerses(context.Context, *GetVersesRequest) (*GetVersesResponse, error)
+	GetArtist(context.Context, *GetArtistRequest) (*Artist, error)
+	// Поиск артиста по точному имени или псевдониму без учёта регистра.
+	LookupArtist(context.Context, *LookupArtistRequest) (*Artist, error)
+	mustEmbedUnimplementedSongsServiceServer()
+}
+
+// UnimplementedSongsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSongsServiceServer struct{}
+
+func (UnimplementedSongsServiceServer) ListSongs(context.Context, *ListSongsRequest) (*ListSongsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSongs not implemented")
+}
+func (UnimplementedSongsServiceServer) StreamSongs(*StreamSongsRequest, grpc.ServerStreamingServer[Song]) error {
+	return status.Error(codes.Unimplemented, "method StreamSongs not implemented")
+}
+func (UnimplementedSongsServiceServer) GetSong(context.Context, *GetSongRequest) (*Song, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSong not implemented")
+}
+func (UnimplementedSongsServiceServer) CreateSong(context.Context, *CreateSongRequest) (*Song, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateSong not implemented")
+}
+func (UnimplementedSongsServiceServer) UpdateSong(context.Context, *UpdateSongRequest) (*Song, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateSong not implemented")
+}
+func (UnimplementedSongsServiceServer) DeleteSong(context.Context, *DeleteSongRequest) (*DeleteSongResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteSong not implemented")
+}
+func (UnimplementedSongsServiceServer) GetVerses(context.Context, *GetVersesRequest) (*GetVersesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetVerses not implemented")
+}
+func (UnimplementedSongsServiceServer) GetArtist(context.Context, *GetArtistRequest) (*Artist, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetArtist not implemented")
+}
+func (UnimplementedSongsServiceServer) LookupArtist(context.Context, *LookupArtistRequest) (*Artist, error) {
+	return nil, status.Error(codes.Unimplemented, "method LookupArtist not implemented")
+}
+func (UnimplementedSongsServiceServer) mustEmbedUnimplementedSongsServiceServer() {}
+func (UnimplementedSongsServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeSongsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SongsServiceServer will
+// result in compilation errors.
+type UnsafeSongsServiceServer interface {
+	mustEmbedUnimplementedSongsServiceServer()
+}
+
+func RegisterSongsServiceServer(s grpc.ServiceRegistrar, srv SongsServiceServer) {
+	// If the following call panics, it indicates UnimplementedSongsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SongsService_ServiceDesc, srv)
+}
+
+func _SongsService_ListSongs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSongsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).ListSongs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_ListSongs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).ListSongs(ctx, req.(*ListSongsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_StreamSongs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSongsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SongsServiceServer).StreamSongs(m, &grpc.GenericServerStream[StreamSongsRequest, Song]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SongsService_StreamSongsServer = grpc.ServerStreamingServer[Song]
+
+func _SongsService_GetSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).GetSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_GetSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).GetSong(ctx, req.(*GetSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_CreateSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).CreateSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_CreateSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).CreateSong(ctx, req.(*CreateSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_UpdateSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).UpdateSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_UpdateSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).UpdateSong(ctx, req.(*UpdateSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_DeleteSong_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSongRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).DeleteSong(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_DeleteSong_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).DeleteSong(ctx, req.(*DeleteSongRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_GetVerses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).GetVerses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_GetVerses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).GetVerses(ctx, req.(*GetVersesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_GetArtist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).GetArtist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_GetArtist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).GetArtist(ctx, req.(*GetArtistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SongsService_LookupArtist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupArtistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SongsServiceServer).LookupArtist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SongsService_LookupArtist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SongsServiceServer).LookupArtist(ctx, req.(*LookupArtistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SongsService_ServiceDesc is the grpc.ServiceDesc for SongsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SongsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "songs.v1.SongsService",
+	HandlerType: (*SongsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSongs",
+			Handler:    _SongsService_ListSongs_Handler,
+		},
+		{
+			MethodName: "GetSong",
+			Handler:    _SongsService_GetSong_Handler,
+		},
+		{
+			MethodName: "CreateSong",
+			Handler:    _SongsService_CreateSong_Handler,
+		},
+		{
+			MethodName: "UpdateSong",
+			Handler:    _SongsService_UpdateSong_Handler,
+		},
+		{
+			MethodName: "DeleteSong",
+			Handler:    _SongsService_DeleteSong_Handler,
+		},
+		{
+			MethodName: "GetVerses",
+			Handler:    _SongsService_GetVerses_Handler,
+		},
+		{
+			MethodName: "GetArtist",
+			Handler:    _SongsService_GetArtist_Handler,
+		},
+		{
+			MethodName: "LookupArtist",
+			Handler:    _SongsService_LookupArtist_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSongs",
+			Handler:       _SongsService_StreamSongs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "songs/v1/songs.proto",
+}